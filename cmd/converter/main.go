@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/stepupdream/golang-support-tool/converter"
+	"github.com/stepupdream/golang-support-tool/separated_value"
+)
+
+// Converts the sheets of the workbooks of a directory into separated value files.
+//
+//	go run ./cmd/converter -in excel -out master -sheet '^[a-z_]+$'
+func main() {
+	inputDirectoryPath := flag.String("in", "", "The directory of the workbooks")
+	outputDirectoryPath := flag.String("out", "", "The directory to write the separated value files")
+	sheet := flag.String("sheet", "", "The regular expression of the sheet names to convert (default all sheets)")
+	delimiter := flag.String("delimiter", ",", `The delimiter of the files, e.g. "\t" for tsv`)
+	extension := flag.String("extension", "", "The extension of the files (default .tsv for tab, otherwise .csv)")
+	flag.Parse()
+
+	if *inputDirectoryPath == "" || *outputDirectoryPath == "" {
+		flag.Usage()
+		log.Fatal("-in and -out are required")
+	}
+
+	var sheetPattern *regexp.Regexp
+	if *sheet != "" {
+		var err error
+		sheetPattern, err = regexp.Compile(*sheet)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	delimiters := []rune(strings.ReplaceAll(*delimiter, `\t`, "\t"))
+	if len(delimiters) != 1 {
+		log.Fatal("-delimiter must be one character")
+	}
+	options := []separated_value.Option{separated_value.WithDelimiter(delimiters[0])}
+	if delimiters[0] == '\t' {
+		options = append(options, separated_value.WithLazyQuotes(true))
+	}
+	if *extension != "" {
+		options = append(options, separated_value.WithExtension(*extension))
+	}
+	separatedValue, err := separated_value.New(options...)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	result := converter.New(separatedValue, sheetPattern).Convert(*inputDirectoryPath, *outputDirectoryPath)
+	log.Printf("converted %d, skipped %d", len(result.Converted), len(result.Skipped))
+}
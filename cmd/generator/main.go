@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"strings"
+
+	"github.com/stepupdream/golang-support-tool/generator"
+	"github.com/stepupdream/golang-support-tool/separated_value"
+)
+
+// Generates Go structs and loaders from the separated value files of a master-data directory.
+//
+//	go run ./cmd/generator -dir master -out model/master.go -package model
+func main() {
+	directoryPath := flag.String("dir", "", "The master-data directory")
+	outputPath := flag.String("out", "", "The Go file to write")
+	packageName := flag.String("package", "master", "The package name of the Go file")
+	delimiter := flag.String("delimiter", ",", `The delimiter of the files, e.g. "\t" for tsv`)
+	extension := flag.String("extension", "", "The extension of the files (default .tsv for tab, otherwise .csv)")
+	flag.Parse()
+
+	if *directoryPath == "" || *outputPath == "" {
+		flag.Usage()
+		log.Fatal("-dir and -out are required")
+	}
+
+	delimiters := []rune(strings.ReplaceAll(*delimiter, `\t`, "\t"))
+	if len(delimiters) != 1 {
+		log.Fatal("-delimiter must be one character")
+	}
+	options := []separated_value.Option{separated_value.WithDelimiter(delimiters[0])}
+	if delimiters[0] == '\t' {
+		options = append(options, separated_value.WithLazyQuotes(true))
+	}
+	if *extension != "" {
+		options = append(options, separated_value.WithExtension(*extension))
+	}
+	separatedValue, err := separated_value.New(options...)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	generator.New(separatedValue, *packageName).WriteFile(*directoryPath, *outputPath)
+}
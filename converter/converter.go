@@ -0,0 +1,248 @@
+package converter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/stepupdream/golang-support-tool/excel"
+	supportFile "github.com/stepupdream/golang-support-tool/file"
+	"github.com/stepupdream/golang-support-tool/separated_value"
+)
+
+// HashFileName The file in the output directory that records the hash and the outputs of every converted workbook.
+const HashFileName = ".excel_hash.json"
+
+// Converter Converts the sheets of the workbooks under a directory into separated value files.
+type Converter struct {
+	separatedValue *separated_value.SeparatedValue
+	sheetPattern   *regexp.Regexp
+}
+
+// Result The workbooks converted and skipped by ConvertE, as paths relative to the input directory.
+// Removed are the workbooks no longer in the input directory, whose outputs have been removed.
+type Result struct {
+	Converted []string
+	Skipped   []string
+	Removed   []string
+}
+
+// workbookHash The entry of a workbook in the hash file.
+type workbookHash struct {
+	Hash    string   `json:"hash"`
+	Outputs []string `json:"outputs"`
+}
+
+// New When sheetPattern is nil, every sheet is converted. Otherwise only the sheets whose name matches it.
+func New(separatedValue *separated_value.SeparatedValue, sheetPattern *regexp.Regexp) *Converter {
+	return &Converter{
+		separatedValue: separatedValue,
+		sheetPattern:   sheetPattern,
+	}
+}
+
+func (converter *Converter) Convert(inputDirectoryPath string, outputDirectoryPath string) Result {
+	result, err := converter.ConvertE(inputDirectoryPath, outputDirectoryPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return result
+}
+
+// ConvertE Writes every sheet of the workbooks under inputDirectoryPath to outputDirectoryPath, mirroring the directories:
+// the sheet "item" of input/sub/book.xlsx becomes output/sub/item.csv. Empty sheets are not written.
+// A workbook whose content has not changed since the last conversion, and whose outputs still exist, is skipped.
+// The outputs of the sheets and of the workbooks that no longer exist are removed.
+func (converter *Converter) ConvertE(inputDirectoryPath string, outputDirectoryPath string) (Result, error) {
+	hashFilePath := filepath.Join(outputDirectoryPath, HashFileName)
+	hashes, err := loadHashes(hashFilePath)
+	if err != nil {
+		return Result{}, err
+	}
+
+	filePaths := excel.GetFilePathRecursive(inputDirectoryPath)
+	sort.Strings(filePaths)
+
+	var result Result
+	outputOwners := map[string]string{}
+	inputPaths := map[string]bool{}
+	for _, filePath := range filePaths {
+		relativePath, err := filepath.Rel(inputDirectoryPath, filePath)
+		if err != nil {
+			return result, errors.Wrap(err, "RelError")
+		}
+		relativePath = filepath.ToSlash(relativePath)
+		inputPaths[relativePath] = true
+
+		hash, err := converter.hash(filePath)
+		if err != nil {
+			return result, err
+		}
+
+		previous, ok := hashes[relativePath]
+		if ok && previous.Hash == hash && outputsExist(outputDirectoryPath, previous.Outputs) {
+			for _, output := range previous.Outputs {
+				outputOwners[output] = relativePath
+			}
+			result.Skipped = append(result.Skipped, relativePath)
+			continue
+		}
+
+		outputs, err := converter.convertWorkbook(filePath, relativePath, outputDirectoryPath, outputOwners)
+		if err != nil {
+			return result, err
+		}
+		if err := removeOutputs(outputDirectoryPath, previous.Outputs, outputOwners); err != nil {
+			return result, err
+		}
+
+		hashes[relativePath] = workbookHash{Hash: hash, Outputs: outputs}
+		// The hash file is saved after every workbook, so that an error does not discard the conversions already done.
+		if err := saveHashes(hashFilePath, hashes); err != nil {
+			return result, err
+		}
+		result.Converted = append(result.Converted, relativePath)
+	}
+
+	var removedPaths []string
+	for relativePath := range hashes {
+		if !inputPaths[relativePath] {
+			removedPaths = append(removedPaths, relativePath)
+		}
+	}
+	sort.Strings(removedPaths)
+	for _, relativePath := range removedPaths {
+		// Another workbook may write the same output now.
+		if err := removeOutputs(outputDirectoryPath, hashes[relativePath].Outputs, outputOwners); err != nil {
+			return result, err
+		}
+		delete(hashes, relativePath)
+		if err := saveHashes(hashFilePath, hashes); err != nil {
+			return result, err
+		}
+		result.Removed = append(result.Removed, relativePath)
+	}
+
+	return result, nil
+}
+
+// removeOutputs Removes the outputs that are not written by any workbook of this conversion.
+func removeOutputs(outputDirectoryPath string, outputs []string, outputOwners map[string]string) error {
+	for _, output := range outputs {
+		if outputOwners[output] != "" {
+			continue
+		}
+		if err := os.Remove(filepath.Join(outputDirectoryPath, output)); err != nil && !os.IsNotExist(err) {
+			return errors.Wrap(err, "RemoveError")
+		}
+	}
+
+	return nil
+}
+
+func (converter *Converter) convertWorkbook(filePath string, relativePath string, outputDirectoryPath string, outputOwners map[string]string) ([]string, error) {
+	sheets, err := excel.LoadE(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var outputs []string
+	for _, sheet := range sheets {
+		if converter.sheetPattern != nil && !converter.sheetPattern.MatchString(sheet.Name) {
+			continue
+		}
+		if len(sheet.Rows) == 0 {
+			continue
+		}
+
+		output := filepath.ToSlash(filepath.Join(filepath.Dir(relativePath), sheet.Name+converter.separatedValue.GetExtension()))
+		if owner, ok := outputOwners[output]; ok {
+			return nil, errors.Errorf("The sheet %s of %s is written to %s, which is also written from %s", sheet.Name, relativePath, output, owner)
+		}
+		outputOwners[output] = relativePath
+
+		outputPath := filepath.Join(outputDirectoryPath, output)
+		if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+			return nil, errors.Wrap(err, "MkdirAllError")
+		}
+		if err := converter.separatedValue.NewFileE(outputPath, sheet.Rows); err != nil {
+			return nil, err
+		}
+		outputs = append(outputs, output)
+	}
+
+	return outputs, nil
+}
+
+// hash Returns the hash of the workbook, of the sheet pattern and of every setting of the written files (see WriteSettings),
+// so that changing any of them converts the workbooks again.
+func (converter *Converter) hash(filePath string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", errors.Wrap(err, "OpenError")
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", errors.Wrap(err, "ReadError")
+	}
+	hash.Write([]byte{0})
+	hash.Write([]byte(converter.separatedValue.WriteSettings()))
+	if converter.sheetPattern != nil {
+		hash.Write([]byte{0})
+		hash.Write([]byte(converter.sheetPattern.String()))
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func loadHashes(hashFilePath string) (map[string]workbookHash, error) {
+	hashes := map[string]workbookHash{}
+	if !supportFile.Exists(hashFilePath) {
+		return hashes, nil
+	}
+
+	content, err := os.ReadFile(hashFilePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "ReadFileError")
+	}
+	if err := json.Unmarshal(content, &hashes); err != nil {
+		return nil, errors.Wrapf(err, "The hash file is broken : %s", hashFilePath)
+	}
+
+	return hashes, nil
+}
+
+func saveHashes(hashFilePath string, hashes map[string]workbookHash) error {
+	content, err := json.MarshalIndent(hashes, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "MarshalError")
+	}
+	if err := os.MkdirAll(filepath.Dir(hashFilePath), 0755); err != nil {
+		return errors.Wrap(err, "MkdirAllError")
+	}
+	if err := os.WriteFile(hashFilePath, append(content, '\n'), 0644); err != nil {
+		return errors.Wrap(err, "WriteFileError")
+	}
+
+	return nil
+}
+
+func outputsExist(outputDirectoryPath string, outputs []string) bool {
+	for _, output := range outputs {
+		if !supportFile.Exists(filepath.Join(outputDirectoryPath, output)) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,137 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+
+	supportFile "github.com/stepupdream/golang-support-tool/file"
+	"github.com/stepupdream/golang-support-tool/separated_value"
+)
+
+func TestConvertE(t *testing.T) {
+	var separatedValue separated_value.SeparatedValue
+	separatedValue.Init("csv", ".csv")
+	outputDirectoryPath := t.TempDir()
+
+	converter := New(&separatedValue, nil)
+	result, err := converter.ConvertE("./test/master", outputDirectoryPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Result{Converted: []string{"item.xlsx", "sub/reward.xlsx"}}); !reflect.DeepEqual(result, want) {
+		t.Errorf("ConvertE() = %v, want %v", result, want)
+	}
+
+	rows, err := separatedValue.LoadE(filepath.Join(outputDirectoryPath, "sub", "reward.csv"), false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]string{{"id", "item_id"}, {"1", "2"}}; !reflect.DeepEqual(rows, want) {
+		t.Errorf("reward.csv = %v, want %v", rows, want)
+	}
+	if !supportFile.Exists(filepath.Join(outputDirectoryPath, "memo.csv")) {
+		t.Error("memo.csv is not written")
+	}
+
+	// Nothing has changed, except that an output was removed.
+	if err := os.Remove(filepath.Join(outputDirectoryPath, "sub", "reward.csv")); err != nil {
+		t.Fatal(err)
+	}
+	result, err = converter.ConvertE("./test/master", outputDirectoryPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Result{Converted: []string{"sub/reward.xlsx"}, Skipped: []string{"item.xlsx"}}); !reflect.DeepEqual(result, want) {
+		t.Errorf("ConvertE() = %v, want %v", result, want)
+	}
+
+	// Another sheet pattern converts the workbooks again, and removes the sheets no longer matching.
+	result, err = New(&separatedValue, regexp.MustCompile("^(item|reward)$")).ConvertE("./test/master", outputDirectoryPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Converted) != 2 {
+		t.Errorf("ConvertE() = %v", result)
+	}
+	if supportFile.Exists(filepath.Join(outputDirectoryPath, "memo.csv")) {
+		t.Error("memo.csv is not removed")
+	}
+}
+
+func TestConvertEWriteSettings(t *testing.T) {
+	var separatedValue separated_value.SeparatedValue
+	separatedValue.Init("csv", ".csv")
+	outputDirectoryPath := t.TempDir()
+
+	if _, err := New(&separatedValue, nil).ConvertE("./test/master", outputDirectoryPath); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the delimiter changes, and the extension is still .csv.
+	semicolon, err := separated_value.New(separated_value.WithDelimiter(';'), separated_value.WithExtension(".csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := New(semicolon, nil).ConvertE("./test/master", outputDirectoryPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Result{Converted: []string{"item.xlsx", "sub/reward.xlsx"}}); !reflect.DeepEqual(result, want) {
+		t.Errorf("ConvertE() = %v, want %v", result, want)
+	}
+	content, err := os.ReadFile(filepath.Join(outputDirectoryPath, "sub", "reward.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "\xEF\xBB\xBFid;item_id\n1;2\n"; string(content) != want {
+		t.Errorf("reward.csv = %q, want %q", content, want)
+	}
+}
+
+func TestConvertERemovedWorkbook(t *testing.T) {
+	var separatedValue separated_value.SeparatedValue
+	separatedValue.Init("csv", ".csv")
+	inputDirectoryPath := t.TempDir()
+	outputDirectoryPath := t.TempDir()
+	for _, name := range []string{"item.xlsx", "sub/reward.xlsx"} {
+		content, err := os.ReadFile(filepath.Join("test", "master", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(inputDirectoryPath, name)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(inputDirectoryPath, name), content, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	converter := New(&separatedValue, nil)
+	if _, err := converter.ConvertE(inputDirectoryPath, outputDirectoryPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(inputDirectoryPath, "sub", "reward.xlsx")); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := converter.ConvertE(inputDirectoryPath, outputDirectoryPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Result{Skipped: []string{"item.xlsx"}, Removed: []string{"sub/reward.xlsx"}}); !reflect.DeepEqual(result, want) {
+		t.Errorf("ConvertE() = %v, want %v", result, want)
+	}
+	if supportFile.Exists(filepath.Join(outputDirectoryPath, "sub", "reward.csv")) {
+		t.Error("reward.csv is not removed")
+	}
+	hashes, err := loadHashes(filepath.Join(outputDirectoryPath, HashFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := hashes["sub/reward.xlsx"]; ok {
+		t.Error("The hash of reward.xlsx is not removed")
+	}
+}
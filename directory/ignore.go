@@ -0,0 +1,194 @@
+package directory
+
+import (
+	"bufio"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// IgnoreFileName The file of ignore rules read in every directory walked by WalkFiles.
+// It is written like .gitignore, and applies to the directory and its subdirectories.
+const IgnoreFileName = ".supportignore"
+
+// DefaultIgnorePatterns The rules applied before the ignore files: the owner files Excel leaves while a workbook is open,
+// the lock files of LibreOffice, and hidden files and directories. An ignore file can re-include them with "!".
+var DefaultIgnorePatterns = []string{"~$*", ".~lock.*#", ".*"}
+
+// ignoreRule One line of an ignore file. base is the directory of the ignore file, to which the pattern is relative.
+type ignoreRule struct {
+	base            string
+	pattern         *regexp.Regexp
+	isNegation      bool
+	isDirectoryOnly bool
+}
+
+// WalkFiles Calls fn with the path of every file under root, in lexical order like filepath.WalkDir,
+// skipping the files and directories matched by DefaultIgnorePatterns and the ignore files.
+func WalkFiles(root string, fn func(path string) error) error {
+	// The rules are looked up by filepath.Dir, which returns a clean path.
+	root = filepath.Clean(root)
+	defaultRules, err := parseIgnoreRules(root, DefaultIgnorePatterns)
+	if err != nil {
+		return err
+	}
+	directoryRules := map[string][]ignoreRule{}
+
+	return filepath.WalkDir(root, func(path string, dirEntry fs.DirEntry, err error) error {
+		if err != nil {
+			return errors.Wrap(err, "failed filepath.WalkDir")
+		}
+
+		rules := defaultRules
+		if path != root {
+			rules = directoryRules[filepath.Dir(path)]
+			if isIgnored(rules, path, dirEntry.IsDir()) {
+				if dirEntry.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+		}
+
+		if !dirEntry.IsDir() {
+			return fn(path)
+		}
+
+		ownRules, err := loadIgnoreFile(path)
+		if err != nil {
+			return err
+		}
+		directoryRules[path] = append(append([]ignoreRule{}, rules...), ownRules...)
+
+		return nil
+	})
+}
+
+// isIgnored The last rule that matches decides, as in .gitignore.
+func isIgnored(rules []ignoreRule, path string, isDirectory bool) bool {
+	isIgnored := false
+	for _, rule := range rules {
+		if rule.isDirectoryOnly && !isDirectory {
+			continue
+		}
+		relativePath, err := filepath.Rel(rule.base, path)
+		if err != nil || strings.HasPrefix(relativePath, "..") {
+			continue
+		}
+		if rule.pattern.MatchString(filepath.ToSlash(relativePath)) {
+			isIgnored = !rule.isNegation
+		}
+	}
+
+	return isIgnored
+}
+
+func loadIgnoreFile(directoryPath string) ([]ignoreRule, error) {
+	ignoreFile, err := os.Open(filepath.Join(directoryPath, IgnoreFileName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "IgnoreFileOpenError")
+	}
+	defer ignoreFile.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(ignoreFile)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "IgnoreFileReadError")
+	}
+
+	rules, err := parseIgnoreRules(directoryPath, lines)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid ignore file : %s", filepath.Join(directoryPath, IgnoreFileName))
+	}
+
+	return rules, nil
+}
+
+// parseIgnoreRules Parses the lines of an ignore file. Blank lines and lines starting with "#" are skipped.
+// A pattern without a slash matches a name at any depth, otherwise it is relative to base.
+// "*" and "?" do not match a slash, "**" matches any number of directories, and a trailing slash matches only directories.
+func parseIgnoreRules(base string, lines []string) ([]ignoreRule, error) {
+	var rules []ignoreRule
+	for _, line := range lines {
+		line = strings.TrimRight(strings.TrimSuffix(line, "\r"), " ")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		rule := ignoreRule{base: base}
+		if strings.HasPrefix(line, "!") {
+			rule.isNegation = true
+			line = line[1:]
+		}
+		if strings.HasPrefix(line, `\`) {
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			rule.isDirectoryOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+
+		prefix := "^(.*/)?"
+		if strings.Contains(line, "/") {
+			prefix = "^"
+			line = strings.TrimPrefix(line, "/")
+		}
+
+		pattern, err := regexp.Compile(prefix + globToRegexp(line) + "$")
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid ignore pattern : %s", line)
+		}
+		rule.pattern = pattern
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+func globToRegexp(glob string) string {
+	var builder strings.Builder
+	for index := 0; index < len(glob); index++ {
+		character := glob[index]
+		switch {
+		case strings.HasPrefix(glob[index:], "**/"):
+			builder.WriteString("(.*/)?")
+			index += 2
+		case strings.HasPrefix(glob[index:], "**"):
+			builder.WriteString(".*")
+			index++
+		case character == '*':
+			builder.WriteString("[^/]*")
+		case character == '?':
+			builder.WriteString("[^/]")
+		case character == '[':
+			end := strings.IndexByte(glob[index+1:], ']')
+			if end < 0 {
+				builder.WriteString(`\[`)
+				continue
+			}
+			class := glob[index+1 : index+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			builder.WriteString("[" + class + "]")
+			index += end + 1
+		case character == '\\' && index+1 < len(glob):
+			index++
+			builder.WriteString(regexp.QuoteMeta(string(glob[index])))
+		default:
+			builder.WriteString(regexp.QuoteMeta(string(character)))
+		}
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,91 @@
+package directory
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestWalkFiles(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"item.xlsx":                    "",
+		"~$item.xlsx":                  "",
+		".~lock.item.xlsx#":            "",
+		".hidden/reward.xlsx":          "",
+		".supportignore":               "# drafts\ndraft/\n*.bak\n!keep.bak\n/root_only.csv\n",
+		"keep.bak":                     "",
+		"old.bak":                      "",
+		"root_only.csv":                "",
+		"draft/item.xlsx":              "",
+		"sub/root_only.csv":            "",
+		"sub/draft.csv":                "",
+		"sub/.supportignore":           "!.keep\nlevel/**/deep.csv\n",
+		"sub/.keep":                    "",
+		"sub/level/a/b/deep.csv":       "",
+		"sub/level/shallow.csv":        "",
+		"other/.~lock.reward.xlsx#":    "",
+		"other/reward.xlsx":            "",
+		"other/.keep":                  "",
+		"other/nested/~$reward.xlsm":   "",
+		"other/nested/reward.xlsm":     "",
+		"other/nested/reward.xlsm.bak": "",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	workingDirectory, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	relativeRoot, err := filepath.Rel(workingDirectory, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"item.xlsx",
+		"keep.bak",
+		"other/nested/reward.xlsm",
+		"other/reward.xlsx",
+		"sub/.keep",
+		"sub/draft.csv",
+		"sub/level/shallow.csv",
+		"sub/root_only.csv",
+	}
+
+	tests := []struct {
+		name string
+		root string
+	}{
+		{name: "clean", root: root},
+		{name: "trailing slash", root: root + string(filepath.Separator)},
+		{name: "dot prefix", root: "." + string(filepath.Separator) + relativeRoot},
+		{name: "dot prefix and trailing slash", root: "." + string(filepath.Separator) + relativeRoot + string(filepath.Separator)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			err := WalkFiles(tt.root, func(path string) error {
+				relativePath, _ := filepath.Rel(tt.root, path)
+				got = append(got, filepath.ToSlash(relativePath))
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("WalkFiles() = %v, want %v", got, want)
+			}
+		})
+	}
+}
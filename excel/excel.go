@@ -1,26 +1,19 @@
 package excel
 
 import (
-	"io/fs"
 	"log"
 	"path/filepath"
 
-	"github.com/pkg/errors"
+	"github.com/stepupdream/golang-support-tool/directory"
 )
 
+// GetFilePathRecursive Returns the xlsx/xlsm files under the path.
+// The owner files Excel leaves while a workbook is open, the lock files of LibreOffice, hidden files
+// and the files matched by the ignore files are skipped (see directory.WalkFiles).
 func GetFilePathRecursive(path string) []string {
 	var paths []string
 
-	// Recursively retrieve directories and files. (use WalkDir since Walk is now deprecated)
-	err := filepath.WalkDir(path, func(path string, info fs.DirEntry, err error) error {
-		if err != nil {
-			return errors.Wrap(err, "failed filepath.WalkDir")
-		}
-
-		if info.IsDir() {
-			return nil
-		}
-
+	err := directory.WalkFiles(path, func(path string) error {
 		extension := filepath.Ext(path)
 		if extension != ".xlsx" && extension != ".xlsm" {
 			return nil
@@ -0,0 +1,505 @@
+package excel
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"io"
+	"log"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Sheet The cells of one worksheet as rows, in the shape SeparatedValue.Load returns.
+// Every row has the same number of columns, and the trailing blank rows and columns are trimmed.
+type Sheet struct {
+	Name string
+	Rows [][]string
+}
+
+// The layouts of date cells. A date cell is formatted as a date, a time or both, according to its number format.
+const (
+	DateLayout     = "2006-01-02"
+	TimeLayout     = "15:04:05"
+	DateTimeLayout = "2006-01-02 15:04:05"
+)
+
+// MaxCells The largest number of cells (rows times columns, blanks included) a sheet may have.
+// A stray cell far from the data would otherwise make every row as long as its column.
+var MaxCells = 10000000
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		Id     string `xml:"Id,attr"`
+		Type   string `xml:"Type,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxWorkbook struct {
+	WorkbookPr struct {
+		Date1904 string `xml:"date1904,attr"`
+	} `xml:"workbookPr"`
+	Sheets []struct {
+		Name  string     `xml:"name,attr"`
+		Attrs []xml.Attr `xml:",any,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+// xlsxString A shared string or an inline string. Rich text is split into runs, and phonetic runs are not part of the text.
+type xlsxString struct {
+	T *string `xml:"t"`
+	R []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (xlsxString xlsxString) text() string {
+	if xlsxString.T != nil {
+		return *xlsxString.T
+	}
+
+	var builder strings.Builder
+	for _, run := range xlsxString.R {
+		builder.WriteString(run.T)
+	}
+
+	return builder.String()
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxString `xml:"si"`
+}
+
+type xlsxStyles struct {
+	NumFmts []struct {
+		NumFmtId   int    `xml:"numFmtId,attr"`
+		FormatCode string `xml:"formatCode,attr"`
+	} `xml:"numFmts>numFmt"`
+	CellXfs []struct {
+		NumFmtId int `xml:"numFmtId,attr"`
+	} `xml:"cellXfs>xf"`
+}
+
+type xlsxWorksheet struct {
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			R  string      `xml:"r,attr"`
+			T  string      `xml:"t,attr"`
+			S  int         `xml:"s,attr"`
+			V  *string     `xml:"v"`
+			Is *xlsxString `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// workbook An opened xlsx/xlsm file.
+type workbook struct {
+	files         map[string]*zip.File
+	sheetNames    []string
+	sheetPaths    map[string]string
+	sharedStrings []string
+	layouts       []string
+	isDate1904    bool
+}
+
+func Load(filePath string) []Sheet {
+	sheets, err := LoadE(filePath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return sheets
+}
+
+// LoadE Reads every worksheet of the xlsx/xlsm file, in the order of the workbook.
+// Numbers are written without exponent and rounded to the 15 significant digits Excel displays,
+// dates are formatted with DateLayout, TimeLayout or DateTimeLayout, and booleans are TRUE or FALSE.
+// Formulas are not calculated: the value cached in the file is used. A sheet larger than MaxCells is an error.
+func LoadE(filePath string) ([]Sheet, error) {
+	zipReader, err := zip.OpenReader(filePath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to open the Excel file : %s", filePath)
+	}
+	defer zipReader.Close()
+
+	book, err := openWorkbook(&zipReader.Reader)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to read the Excel file : %s", filePath)
+	}
+
+	var sheets []Sheet
+	for _, name := range book.sheetNames {
+		rows, err := book.sheet(name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to read the sheet %s of the Excel file : %s", name, filePath)
+		}
+		sheets = append(sheets, Sheet{Name: name, Rows: rows})
+	}
+
+	return sheets, nil
+}
+
+func LoadSheet(filePath string, sheetName string) [][]string {
+	rows, err := LoadSheetE(filePath, sheetName)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return rows
+}
+
+// LoadSheetE Same as LoadE, but reads only the worksheet named sheetName.
+func LoadSheetE(filePath string, sheetName string) ([][]string, error) {
+	zipReader, err := zip.OpenReader(filePath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to open the Excel file : %s", filePath)
+	}
+	defer zipReader.Close()
+
+	book, err := openWorkbook(&zipReader.Reader)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to read the Excel file : %s", filePath)
+	}
+	if _, ok := book.sheetPaths[sheetName]; !ok {
+		return nil, errors.Errorf("The sheet %s could not be found : %s", sheetName, filePath)
+	}
+
+	rows, err := book.sheet(sheetName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to read the sheet %s of the Excel file : %s", sheetName, filePath)
+	}
+
+	return rows, nil
+}
+
+func openWorkbook(zipReader *zip.Reader) (*workbook, error) {
+	book := &workbook{files: map[string]*zip.File{}, sheetPaths: map[string]string{}}
+	for _, file := range zipReader.File {
+		book.files[file.Name] = file
+	}
+
+	workbookPath := "xl/workbook.xml"
+	var rootRelationships xlsxRelationships
+	if err := book.decode("_rels/.rels", &rootRelationships); err != nil {
+		return nil, err
+	}
+	for _, relationship := range rootRelationships.Relationships {
+		if strings.HasSuffix(relationship.Type, "/officeDocument") {
+			workbookPath = resolveTarget("", relationship.Target)
+		}
+	}
+
+	var xmlWorkbook xlsxWorkbook
+	if err := book.decode(workbookPath, &xmlWorkbook); err != nil {
+		return nil, err
+	}
+	book.isDate1904 = xmlWorkbook.WorkbookPr.Date1904 == "1" || xmlWorkbook.WorkbookPr.Date1904 == "true"
+
+	var workbookRelationships xlsxRelationships
+	relationshipsPath := path.Join(path.Dir(workbookPath), "_rels", path.Base(workbookPath)+".rels")
+	if err := book.decode(relationshipsPath, &workbookRelationships); err != nil {
+		return nil, err
+	}
+	targets := map[string]string{}
+	for _, relationship := range workbookRelationships.Relationships {
+		target := resolveTarget(path.Dir(workbookPath), relationship.Target)
+		targets[relationship.Id] = target
+
+		switch {
+		case strings.HasSuffix(relationship.Type, "/sharedStrings"):
+			var sharedStrings xlsxSharedStrings
+			if err := book.decode(target, &sharedStrings); err != nil {
+				return nil, err
+			}
+			for _, item := range sharedStrings.Items {
+				book.sharedStrings = append(book.sharedStrings, item.text())
+			}
+		case strings.HasSuffix(relationship.Type, "/styles"):
+			var styles xlsxStyles
+			if err := book.decode(target, &styles); err != nil {
+				return nil, err
+			}
+			book.layouts = dateLayouts(styles)
+		}
+	}
+
+	for _, xmlSheet := range xmlWorkbook.Sheets {
+		// The relationship id is r:id, whose namespace differs between transitional and strict files.
+		for _, attr := range xmlSheet.Attrs {
+			if attr.Name.Local == "id" && attr.Name.Space != "" {
+				book.sheetNames = append(book.sheetNames, xmlSheet.Name)
+				book.sheetPaths[xmlSheet.Name] = targets[attr.Value]
+			}
+		}
+	}
+
+	return book, nil
+}
+
+// decode Unmarshals a part of the file. A part that does not exist is left as the zero value.
+func (book *workbook) decode(name string, value interface{}) error {
+	file, ok := book.files[name]
+	if !ok {
+		return nil
+	}
+
+	reader, err := file.Open()
+	if err != nil {
+		return errors.Wrapf(err, "Failed to open %s", name)
+	}
+	defer reader.Close()
+
+	if err := xml.NewDecoder(reader).Decode(value); err != nil && err != io.EOF {
+		return errors.Wrapf(err, "Failed to parse %s", name)
+	}
+
+	return nil
+}
+
+func (book *workbook) sheet(name string) ([][]string, error) {
+	sheetPath := book.sheetPaths[name]
+	if _, ok := book.files[sheetPath]; !ok {
+		return nil, errors.Errorf("The part %s could not be found", sheetPath)
+	}
+
+	var worksheet xlsxWorksheet
+	if err := book.decode(sheetPath, &worksheet); err != nil {
+		return nil, err
+	}
+
+	cells := map[[2]int]string{}
+	height, width := 0, 0
+	rowNumber := 0
+	for _, row := range worksheet.Rows {
+		// r is omitted by some writers, in which case the rows are consecutive.
+		if row.R > 0 {
+			rowNumber = row.R - 1
+		}
+		columnNumber := 0
+		for _, cell := range row.Cells {
+			if cell.R != "" {
+				cellRow, cellColumn, err := cellPosition(cell.R)
+				if err != nil {
+					return nil, err
+				}
+				rowNumber, columnNumber = cellRow, cellColumn
+			}
+
+			var value string
+			switch cell.T {
+			case "s":
+				if cell.V != nil {
+					index, err := strconv.Atoi(*cell.V)
+					if err != nil || index < 0 || index >= len(book.sharedStrings) {
+						return nil, errors.Errorf("Invalid shared string index %q : %s", *cell.V, cell.R)
+					}
+					value = book.sharedStrings[index]
+				}
+			case "inlineStr":
+				if cell.Is != nil {
+					value = cell.Is.text()
+				}
+			case "b":
+				if cell.V != nil {
+					value = "FALSE"
+					if *cell.V == "1" {
+						value = "TRUE"
+					}
+				}
+			case "str", "e", "d":
+				if cell.V != nil {
+					value = *cell.V
+				}
+			default:
+				if cell.V != nil {
+					value = book.number(*cell.V, cell.S)
+				}
+			}
+
+			if value != "" {
+				cells[[2]int{rowNumber, columnNumber}] = value
+				if rowNumber+1 > height {
+					height = rowNumber + 1
+				}
+				if columnNumber+1 > width {
+					width = columnNumber + 1
+				}
+			}
+			columnNumber++
+		}
+		rowNumber++
+	}
+
+	if width > 0 && height > MaxCells/width {
+		return nil, errors.Errorf("The sheet has %d rows and %d columns, more cells than MaxCells %d", height, width, MaxCells)
+	}
+
+	rows := make([][]string, height)
+	for rowNumber := range rows {
+		rows[rowNumber] = make([]string, width)
+		for columnNumber := range rows[rowNumber] {
+			rows[rowNumber][columnNumber] = cells[[2]int{rowNumber, columnNumber}]
+		}
+	}
+
+	return rows, nil
+}
+
+// number Formats a numeric cell, as a date when its style has a date format.
+func (book *workbook) number(value string, style int) string {
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return value
+	}
+
+	if style >= 0 && style < len(book.layouts) && book.layouts[style] != "" {
+		return book.date(number).Format(book.layouts[style])
+	}
+
+	// Excel displays 15 significant digits, which removes the error of the binary representation.
+	rounded, _ := strconv.ParseFloat(strconv.FormatFloat(number, 'g', 15, 64), 64)
+
+	return strconv.FormatFloat(rounded, 'f', -1, 64)
+}
+
+// date Converts a serial date number into a time.
+func (book *workbook) date(serial float64) time.Time {
+	base := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	if book.isDate1904 {
+		base = time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)
+	} else if serial < 60 {
+		// Excel treats 1900 as a leap year, so the serials before the nonexistent 1900-02-29 are one day off.
+		serial++
+	}
+
+	seconds := math.Round(serial * 24 * 60 * 60)
+
+	return base.Add(time.Duration(seconds) * time.Second)
+}
+
+// dateLayouts Returns the layout of every cell style, or an empty string for a style that is not a date.
+func dateLayouts(styles xlsxStyles) []string {
+	formatCodes := map[int]string{}
+	for _, numFmt := range styles.NumFmts {
+		formatCodes[numFmt.NumFmtId] = numFmt.FormatCode
+	}
+
+	layouts := make([]string, len(styles.CellXfs))
+	for index, xf := range styles.CellXfs {
+		if formatCode, ok := formatCodes[xf.NumFmtId]; ok {
+			layouts[index] = formatCodeLayout(formatCode)
+		} else {
+			layouts[index] = builtInLayout(xf.NumFmtId)
+		}
+	}
+
+	return layouts
+}
+
+// builtInLayout The built-in number formats that are dates, including the ones of the Japanese locale.
+func builtInLayout(numFmtId int) string {
+	switch {
+	case numFmtId >= 14 && numFmtId <= 17,
+		numFmtId >= 27 && numFmtId <= 31,
+		numFmtId == 36,
+		numFmtId >= 50 && numFmtId <= 54,
+		numFmtId == 57 || numFmtId == 58:
+		return DateLayout
+	case numFmtId >= 18 && numFmtId <= 21,
+		numFmtId >= 32 && numFmtId <= 35,
+		numFmtId >= 45 && numFmtId <= 47,
+		numFmtId == 55 || numFmtId == 56:
+		return TimeLayout
+	case numFmtId == 22:
+		return DateTimeLayout
+	}
+
+	return ""
+}
+
+// formatCodeLayout Decides whether a custom number format is a date, a time or both from its tokens,
+// ignoring quoted text, escaped characters and sections in brackets other than elapsed time.
+func formatCodeLayout(formatCode string) string {
+	// Only the first section (for positive numbers) matters.
+	var builder strings.Builder
+	isQuoted := false
+	for index := 0; index < len(formatCode); index++ {
+		character := formatCode[index]
+		switch {
+		case character == '"':
+			isQuoted = !isQuoted
+		case isQuoted:
+		case character == '\\' || character == '_' || character == '*':
+			index++
+		case character == '[':
+			end := strings.IndexByte(formatCode[index:], ']')
+			if end < 0 {
+				index = len(formatCode)
+				continue
+			}
+			section := strings.ToLower(formatCode[index+1 : index+end])
+			if section != "" && strings.Trim(section, "hms") == "" {
+				builder.WriteString(section)
+			}
+			index += end
+		case character == ';':
+			index = len(formatCode)
+		default:
+			builder.WriteByte(character)
+		}
+	}
+	tokens := strings.ToLower(builder.String())
+	tokens = strings.ReplaceAll(tokens, "am/pm", "")
+	tokens = strings.ReplaceAll(tokens, "a/p", "")
+	if strings.Contains(tokens, "general") {
+		return ""
+	}
+
+	isTime := strings.ContainsAny(tokens, "hs")
+	isDate := strings.ContainsAny(tokens, "yd") || (strings.Contains(tokens, "m") && !isTime)
+	switch {
+	case isDate && isTime:
+		return DateTimeLayout
+	case isDate:
+		return DateLayout
+	case isTime:
+		return TimeLayout
+	}
+
+	return ""
+}
+
+// cellPosition Converts a cell reference such as "B3" into zero-based row and column numbers.
+func cellPosition(reference string) (int, int, error) {
+	column := 0
+	index := 0
+	for ; index < len(reference); index++ {
+		character := reference[index]
+		if character < 'A' || character > 'Z' {
+			break
+		}
+		column = column*26 + int(character-'A') + 1
+	}
+
+	row, err := strconv.Atoi(reference[index:])
+	if err != nil || column == 0 || row <= 0 {
+		return 0, 0, errors.Errorf("Invalid cell reference : %s", reference)
+	}
+
+	return row - 1, column - 1, nil
+}
+
+// resolveTarget Resolves the target of a relationship, which is relative to the directory of its source unless it starts with a slash.
+func resolveTarget(directory string, target string) string {
+	if strings.HasPrefix(target, "/") {
+		return strings.TrimPrefix(target, "/")
+	}
+
+	return path.Join(directory, target)
+}
@@ -0,0 +1,167 @@
+package excel
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeWorkbook Writes an xlsx file made of the parts, keyed by the path in the archive.
+func writeWorkbook(t *testing.T, parts map[string]string) string {
+	t.Helper()
+
+	filePath := filepath.Join(t.TempDir(), "book.xlsx")
+	file, err := os.Create(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	zipWriter := zip.NewWriter(file)
+	for name, content := range parts {
+		writer, err := zipWriter.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := writer.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zipWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return filePath
+}
+
+var testParts = map[string]string{
+	"_rels/.rels": `<?xml version="1.0" encoding="UTF-8"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`,
+	"xl/workbook.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="item" sheetId="1" r:id="rId1"/><sheet name="empty" sheetId="2" r:id="rId2"/></sheets>
+</workbook>`,
+	"xl/_rels/workbook.xml.rels": `<?xml version="1.0" encoding="UTF-8"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="/xl/worksheets/sheet2.xml"/>
+<Relationship Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/sharedStrings" Target="sharedStrings.xml"/>
+<Relationship Id="rId4" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
+</Relationships>`,
+	"xl/sharedStrings.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<si><t>id</t></si>
+<si><t>name</t></si>
+<si><r><t>剣</t></r><r><t>A</t></r><rPh sb="0" eb="1"><t>ケン</t></rPh></si>
+</sst>`,
+	"xl/styles.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<numFmts><numFmt numFmtId="164" formatCode="yyyy/mm/dd\ hh:mm"/><numFmt numFmtId="165" formatCode="#,##0&quot;d&quot;"/></numFmts>
+<cellXfs><xf numFmtId="0"/><xf numFmtId="14"/><xf numFmtId="164"/><xf numFmtId="165"/></cellXfs>
+</styleSheet>`,
+	"xl/worksheets/sheet1.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<sheetData>
+<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="C1" t="inlineStr"><is><t>price</t></is></c><c r="D1" t="inlineStr"><is><t>released_at</t></is></c><c r="E1" t="inlineStr"><is><t>is_sale</t></is></c></row>
+<row r="2"><c r="A2"><v>1</v></c><c r="B2" t="s"><v>2</v></c><c r="C2" s="3"><v>0.30000000000000004</v></c><c r="D2" s="1"><v>45000</v></c><c r="E2" t="b"><v>1</v></c></row>
+<row r="4"><c r="A4"><v>1E-3</v></c><c r="C4" t="str"><f>A4*2</f><v>x</v></c><c r="D4" s="2"><v>45000.5</v></c><c r="E4" t="b"><v>0</v></c><c r="G4" s="1"/></row>
+</sheetData>
+</worksheet>`,
+	"xl/worksheets/sheet2.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData/></worksheet>`,
+}
+
+func TestLoadE(t *testing.T) {
+	filePath := writeWorkbook(t, testParts)
+
+	got, err := LoadE(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Sheet{
+		{
+			Name: "item",
+			Rows: [][]string{
+				{"id", "name", "price", "released_at", "is_sale"},
+				{"1", "剣A", "0.3", "2023-03-15", "TRUE"},
+				{"", "", "", "", ""},
+				{"0.001", "", "x", "2023-03-15 12:00:00", "FALSE"},
+			},
+		},
+		{Name: "empty", Rows: [][]string{}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadE() = %v, want %v", got, want)
+	}
+}
+
+func TestLoadSheetE(t *testing.T) {
+	filePath := writeWorkbook(t, testParts)
+
+	tests := []struct {
+		name      string
+		sheetName string
+		wantRows  int
+		wantErr   bool
+	}{
+		{name: "exists", sheetName: "item", wantRows: 4},
+		{name: "not exists", sheetName: "reward", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LoadSheetE(filePath, tt.sheetName)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadSheetE() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != tt.wantRows {
+				t.Errorf("LoadSheetE() = %v, want %d rows", got, tt.wantRows)
+			}
+		})
+	}
+}
+
+func TestLoadSheetETooManyCells(t *testing.T) {
+	parts := map[string]string{}
+	for name, content := range testParts {
+		parts[name] = content
+	}
+	// A stray cell in the last column of a far row.
+	parts["xl/worksheets/sheet2.xml"] = `<?xml version="1.0" encoding="UTF-8"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<sheetData><row r="1"><c r="A1" t="inlineStr"><is><t>id</t></is></c></row><row r="100000"><c r="XFD100000"><v>1</v></c></row></sheetData>
+</worksheet>`
+	filePath := writeWorkbook(t, parts)
+
+	if _, err := LoadSheetE(filePath, "empty"); err == nil {
+		t.Error("LoadSheetE() error = nil")
+	}
+	if _, err := LoadSheetE(filePath, "item"); err != nil {
+		t.Errorf("LoadSheetE() error = %v", err)
+	}
+}
+
+func TestFormatCodeLayout(t *testing.T) {
+	tests := []struct {
+		formatCode string
+		want       string
+	}{
+		{formatCode: "yyyy/mm/dd", want: DateLayout},
+		{formatCode: "h:mm:ss AM/PM", want: TimeLayout},
+		{formatCode: "[h]:mm", want: TimeLayout},
+		{formatCode: "yyyy-mm-dd hh:mm:ss", want: DateTimeLayout},
+		{formatCode: `[Red]#,##0"days"`, want: ""},
+		{formatCode: "0.00E+00", want: ""},
+		{formatCode: "General", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.formatCode, func(t *testing.T) {
+			if got := formatCodeLayout(tt.formatCode); got != tt.want {
+				t.Errorf("formatCodeLayout() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package excel
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// The widths of the columns, in characters. The width of a column fits its widest cell within this range.
+const (
+	MinColumnWidth = 8
+	MaxColumnWidth = 60
+)
+
+const contentTypesXml = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+	`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`
+
+const rootRelationshipsXml = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+// stylesXml The cell style 0 is the default, and 1 is the header: bold on a light gray fill with a bottom border.
+const stylesXml = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="3"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill>` +
+	`<fill><patternFill patternType="solid"><fgColor rgb="FFD9D9D9"/><bgColor indexed="64"/></patternFill></fill></fills>` +
+	`<borders count="2"><border><left/><right/><top/><bottom/><diagonal/></border>` +
+	`<border><left/><right/><top/><bottom style="thin"><color auto="1"/></bottom><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="2" borderId="1" xfId="0" applyFont="1" applyFill="1" applyBorder="1"/></cellXfs>` +
+	`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>` +
+	`</styleSheet>`
+
+func NewFile(path string, sheets []Sheet) {
+	err := NewFileE(path, sheets)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// NewFileE Writes the sheets to an xlsx file, one worksheet each, in the shape LoadE reads.
+// The first row of every sheet is styled as a header and frozen, and the widths of the columns fit their content.
+// Cells that are numbers written in the shortest form (e.g. "12" or "0.5", but not "007") are written as numbers.
+func NewFileE(path string, sheets []Sheet) (err error) {
+	if err := validateSheetNames(sheets); err != nil {
+		return errors.Wrapf(err, "Failed to write the Excel file : %s", path)
+	}
+
+	excelFile, err := os.Create(path)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to create the Excel file : %s", path)
+	}
+	defer func(excelFile *os.File) {
+		closeErr := excelFile.Close()
+		if closeErr != nil && err == nil {
+			err = errors.Wrapf(closeErr, "Failed to close the Excel file : %s", path)
+		}
+	}(excelFile)
+
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{name: "[Content_Types].xml", content: contentTypes(len(sheets))},
+		{name: "_rels/.rels", content: rootRelationshipsXml},
+		{name: "xl/workbook.xml", content: workbookXml(sheets)},
+		{name: "xl/_rels/workbook.xml.rels", content: workbookRelationshipsXml(len(sheets))},
+		{name: "xl/styles.xml", content: stylesXml},
+	}
+	for index, sheet := range sheets {
+		parts = append(parts, struct {
+			name    string
+			content string
+		}{name: "xl/worksheets/sheet" + strconv.Itoa(index+1) + ".xml", content: worksheetXml(sheet.Rows)})
+	}
+
+	zipWriter := zip.NewWriter(excelFile)
+	for _, part := range parts {
+		// The modified time is left out, so that the same sheets always make the same file.
+		writer, err := zipWriter.CreateHeader(&zip.FileHeader{Name: part.name, Method: zip.Deflate})
+		if err != nil {
+			return errors.Wrapf(err, "Failed to write the Excel file : %s", path)
+		}
+		if _, err := writer.Write([]byte(part.content)); err != nil {
+			return errors.Wrapf(err, "Failed to write the Excel file : %s", path)
+		}
+	}
+	if err := zipWriter.Close(); err != nil {
+		return errors.Wrapf(err, "Failed to write the Excel file : %s", path)
+	}
+
+	return nil
+}
+
+// validateSheetNames Excel requires the names of the sheets to be unique ignoring case, 31 characters or less, without []:*?/\.
+func validateSheetNames(sheets []Sheet) error {
+	if len(sheets) == 0 {
+		return errors.New("A workbook needs at least one sheet")
+	}
+
+	names := map[string]bool{}
+	for _, sheet := range sheets {
+		if sheet.Name == "" || len([]rune(sheet.Name)) > 31 || strings.ContainsAny(sheet.Name, `[]:*?/\`) {
+			return errors.Errorf("Invalid sheet name : %q", sheet.Name)
+		}
+		if names[strings.ToLower(sheet.Name)] {
+			return errors.Errorf("Duplicate sheet name : %s", sheet.Name)
+		}
+		names[strings.ToLower(sheet.Name)] = true
+	}
+
+	return nil
+}
+
+func contentTypes(sheetCount int) string {
+	var builder strings.Builder
+	builder.WriteString(contentTypesXml)
+	for index := 1; index <= sheetCount; index++ {
+		builder.WriteString(`<Override PartName="/xl/worksheets/sheet` + strconv.Itoa(index) + `.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`)
+	}
+	builder.WriteString(`</Types>`)
+
+	return builder.String()
+}
+
+func workbookXml(sheets []Sheet) string {
+	var builder strings.Builder
+	builder.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	for index, sheet := range sheets {
+		number := strconv.Itoa(index + 1)
+		builder.WriteString(`<sheet name="` + escape(sheet.Name) + `" sheetId="` + number + `" r:id="rId` + number + `"/>`)
+	}
+	builder.WriteString(`</sheets></workbook>`)
+
+	return builder.String()
+}
+
+// workbookRelationshipsXml The worksheets are rId1 to rIdN, and the styles follow them.
+func workbookRelationshipsXml(sheetCount int) string {
+	var builder strings.Builder
+	builder.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for index := 1; index <= sheetCount; index++ {
+		number := strconv.Itoa(index)
+		builder.WriteString(`<Relationship Id="rId` + number + `" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet` + number + `.xml"/>`)
+	}
+	builder.WriteString(`<Relationship Id="rId` + strconv.Itoa(sheetCount+1) + `" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`)
+	builder.WriteString(`</Relationships>`)
+
+	return builder.String()
+}
+
+func worksheetXml(rows [][]string) string {
+	var builder strings.Builder
+	builder.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+
+	if len(rows) > 1 {
+		builder.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+	}
+
+	widths := columnWidths(rows)
+	if len(widths) != 0 {
+		builder.WriteString(`<cols>`)
+		for index, width := range widths {
+			number := strconv.Itoa(index + 1)
+			builder.WriteString(`<col min="` + number + `" max="` + number + `" width="` + strconv.Itoa(width) + `" customWidth="1"/>`)
+		}
+		builder.WriteString(`</cols>`)
+	}
+
+	builder.WriteString(`<sheetData>`)
+	for rowIndex, row := range rows {
+		rowNumber := strconv.Itoa(rowIndex + 1)
+		builder.WriteString(`<row r="` + rowNumber + `">`)
+		for columnIndex, value := range row {
+			if value == "" && rowIndex != 0 {
+				continue
+			}
+
+			reference := columnName(columnIndex) + rowNumber
+			style := ""
+			if rowIndex == 0 {
+				style = ` s="1"`
+			}
+			if isNumber(value) {
+				builder.WriteString(`<c r="` + reference + `"` + style + `><v>` + value + `</v></c>`)
+			} else {
+				builder.WriteString(`<c r="` + reference + `"` + style + ` t="inlineStr"><is><t xml:space="preserve">` + escape(value) + `</t></is></c>`)
+			}
+		}
+		builder.WriteString(`</row>`)
+	}
+	builder.WriteString(`</sheetData></worksheet>`)
+
+	return builder.String()
+}
+
+// columnWidths A character of East Asian scripts is counted as two, since it is displayed twice as wide.
+func columnWidths(rows [][]string) []int {
+	var widths []int
+	for _, row := range rows {
+		for index, value := range row {
+			for len(widths) <= index {
+				widths = append(widths, MinColumnWidth)
+			}
+
+			width := 2
+			for _, r := range value {
+				width++
+				if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) || (r >= 0xFF01 && r <= 0xFF60) {
+					width++
+				}
+			}
+			if width > MaxColumnWidth {
+				width = MaxColumnWidth
+			}
+			if width > widths[index] {
+				widths[index] = width
+			}
+		}
+	}
+
+	return widths
+}
+
+// isNumber Returns true if the value is a number that Excel displays as written.
+func isNumber(value string) bool {
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil || strconv.FormatFloat(number, 'f', -1, 64) != value {
+		return false
+	}
+
+	// Excel keeps 15 significant digits.
+	digits := strings.TrimLeft(strings.Replace(strings.TrimPrefix(value, "-"), ".", "", 1), "0")
+
+	return len(digits) <= 15
+}
+
+// columnName Converts a zero-based column number into the name of the column, such as "A" or "AB".
+func columnName(columnNumber int) string {
+	name := ""
+	for columnNumber++; columnNumber > 0; columnNumber = (columnNumber - 1) / 26 {
+		name = string(rune('A'+(columnNumber-1)%26)) + name
+	}
+
+	return name
+}
+
+func escape(value string) string {
+	var buffer bytes.Buffer
+	_ = xml.EscapeText(&buffer, []byte(value))
+
+	return buffer.String()
+}
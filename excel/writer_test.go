@@ -0,0 +1,95 @@
+package excel
+
+import (
+	"archive/zip"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewFileE(t *testing.T) {
+	sheets := []Sheet{
+		{
+			Name: "item",
+			Rows: [][]string{
+				{"id", "name", "code", "rate", "memo"},
+				{"1", "剣 & <盾>", "007", "0.5", ""},
+				{"2", " shield", "1e5", "-3", "line\nbreak"},
+			},
+		},
+		{Name: "empty", Rows: [][]string{}},
+	}
+	filePath := filepath.Join(t.TempDir(), "book.xlsx")
+	if err := NewFileE(filePath, sheets); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := LoadE(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, sheets) {
+		t.Errorf("LoadE() = %q, want %q", got, sheets)
+	}
+
+	zipReader, err := zip.OpenReader(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zipReader.Close()
+	reader, err := zipReader.Open("xl/worksheets/sheet1.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`state="frozen"`, `<col min="2" max="2" width="11" customWidth="1"/>`, `<c r="A1" s="1" t="inlineStr">`, `<c r="A2"><v>1</v></c>`} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("sheet1.xml does not contain %s", want)
+		}
+	}
+}
+
+func TestNewFileEInvalidSheetName(t *testing.T) {
+	tests := []struct {
+		name   string
+		sheets []Sheet
+	}{
+		{name: "no sheet", sheets: nil},
+		{name: "blank", sheets: []Sheet{{Name: ""}}},
+		{name: "invalid character", sheets: []Sheet{{Name: "item/reward"}}},
+		{name: "too long", sheets: []Sheet{{Name: strings.Repeat("a", 32)}}},
+		{name: "duplicate", sheets: []Sheet{{Name: "Item"}, {Name: "item"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := NewFileE(filepath.Join(t.TempDir(), "book.xlsx"), tt.sheets); err == nil {
+				t.Error("NewFileE() error = nil")
+			}
+		})
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	tests := []struct {
+		columnNumber int
+		want         string
+	}{
+		{columnNumber: 0, want: "A"},
+		{columnNumber: 25, want: "Z"},
+		{columnNumber: 26, want: "AA"},
+		{columnNumber: 701, want: "ZZ"},
+		{columnNumber: 702, want: "AAA"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := columnName(tt.columnNumber); got != tt.want {
+				t.Errorf("columnName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,272 @@
+package generator
+
+import (
+	"bytes"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+	supportFile "github.com/stepupdream/golang-support-tool/file"
+	"github.com/stepupdream/golang-support-tool/separated_value"
+)
+
+// Generator Writes one Go struct and one typed loader per separated value file of a master-data directory.
+type Generator struct {
+	separatedValue *separated_value.SeparatedValue
+	packageName    string
+}
+
+// table The struct generated from one file.
+type table struct {
+	name   string
+	fields []tableField
+}
+
+type tableField struct {
+	name   string
+	goType string
+	tag    string
+}
+
+func New(separatedValue *separated_value.SeparatedValue, packageName string) *Generator {
+	return &Generator{
+		separatedValue: separatedValue,
+		packageName:    packageName,
+	}
+}
+
+func (generator *Generator) WriteFile(directoryPath string, outputPath string) {
+	err := generator.WriteFileE(directoryPath, outputPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// WriteFileE Generates the source of the files under directoryPath and writes it to outputPath.
+func (generator *Generator) WriteFileE(directoryPath string, outputPath string) error {
+	source, err := generator.GenerateE(directoryPath)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return errors.Wrap(err, "MkdirAllError")
+	}
+	if err := os.WriteFile(outputPath, source, 0644); err != nil {
+		return errors.Wrap(err, "WriteFileError")
+	}
+
+	return nil
+}
+
+// GenerateE Returns the formatted Go source for the files under directoryPath.
+// The types of the fields are taken from the schema of the file when there is one, otherwise inferred from the values.
+func (generator *Generator) GenerateE(directoryPath string) ([]byte, error) {
+	filePaths, err := generator.separatedValue.GetFilePathRecursive(directoryPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "GetFilePathRecursiveError")
+	}
+	sort.Strings(filePaths)
+
+	var tables []table
+	names := map[string]string{}
+	for _, filePath := range filePaths {
+		table, err := generator.table(filePath)
+		if err != nil {
+			return nil, err
+		}
+		if otherPath, ok := names[table.name]; ok {
+			return nil, errors.Errorf("The struct name %s is generated from both %s and %s", table.name, otherPath, filePath)
+		}
+		names[table.name] = filePath
+		tables = append(tables, table)
+	}
+
+	return generator.render(tables)
+}
+
+func (generator *Generator) table(filePath string) (table, error) {
+	rows, err := generator.separatedValue.LoadE(filePath, true, true)
+	if err != nil {
+		return table{}, err
+	}
+	if len(rows) == 0 {
+		return table{}, errors.Errorf("The header row could not be found : %s", filePath)
+	}
+
+	schema, err := generator.separatedValue.SchemaFor(filePath)
+	if err != nil {
+		return table{}, err
+	}
+
+	result := table{name: identifier(supportFile.GetNameWithoutExtension(filePath))}
+	fieldNames := map[string]bool{}
+	for columnNumber, columnName := range rows[0] {
+		name := identifier(columnName)
+		if fieldNames[name] {
+			return table{}, errors.Errorf("The field name %s is generated twice : %s", name, filePath)
+		}
+		fieldNames[name] = true
+
+		goType, layout := inferType(rows[1:], columnNumber)
+		if column, ok := findColumn(schema, columnName); ok {
+			goType, layout = schemaType(column)
+		}
+
+		tag := columnName
+		if layout != "" {
+			tag += ",layout=" + layout
+		}
+		result.fields = append(result.fields, tableField{name: name, goType: goType, tag: tag})
+	}
+
+	return result, nil
+}
+
+func (generator *Generator) render(tables []table) ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString("// Code generated by golang-support-tool/generator. DO NOT EDIT.\n\n")
+	buffer.WriteString("package " + generator.packageName + "\n\n")
+
+	isTimeUsed := false
+	for _, table := range tables {
+		for _, field := range table.fields {
+			if strings.Contains(field.goType, "time.Time") {
+				isTimeUsed = true
+			}
+		}
+	}
+	buffer.WriteString("import (\n")
+	if isTimeUsed {
+		buffer.WriteString("\"time\"\n\n")
+	}
+	buffer.WriteString("\"github.com/stepupdream/golang-support-tool/separated_value\"\n)\n")
+
+	for _, table := range tables {
+		buffer.WriteString("\ntype " + table.name + " struct {\n")
+		for _, field := range table.fields {
+			buffer.WriteString(field.name + " " + field.goType + " `sv:" + strconv.Quote(field.tag) + "`\n")
+		}
+		buffer.WriteString("}\n")
+
+		buffer.WriteString("\nfunc Load" + table.name + "(separatedValue *separated_value.SeparatedValue, filePath string) ([]" + table.name + ", error) {\n")
+		buffer.WriteString("return separated_value.LoadIntoE[" + table.name + "](separatedValue, filePath, nil)\n")
+		buffer.WriteString("}\n")
+	}
+
+	source, err := format.Source(buffer.Bytes())
+	if err != nil {
+		return nil, errors.Wrap(err, "FormatSourceError")
+	}
+
+	return source, nil
+}
+
+// inferType Infers the type of the column from its non-blank values: int, float64, bool or string.
+// A row shorter than the header is blank in the missing columns (see separated_value.WithFieldsPerRecord).
+func inferType(rows [][]string, columnNumber int) (string, string) {
+	isInt, isFloat, isBool := true, true, true
+	isEmpty := true
+	for _, row := range rows {
+		if columnNumber >= len(row) {
+			continue
+		}
+		value := row[columnNumber]
+		if value == "" {
+			continue
+		}
+		isEmpty = false
+
+		if _, err := strconv.Atoi(value); err != nil {
+			isInt = false
+		}
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			isFloat = false
+		}
+		if value != "true" && value != "false" {
+			isBool = false
+		}
+	}
+
+	switch {
+	case isEmpty:
+		return "string", ""
+	case isInt:
+		return "int", ""
+	case isFloat:
+		return "float64", ""
+	case isBool:
+		return "bool", ""
+	}
+
+	return "string", ""
+}
+
+func findColumn(schema *separated_value.Schema, name string) (separated_value.Column, bool) {
+	if schema == nil {
+		return separated_value.Column{}, false
+	}
+	for _, column := range schema.Columns {
+		if column.Name == name {
+			return column, true
+		}
+	}
+
+	return separated_value.Column{}, false
+}
+
+func schemaType(column separated_value.Column) (string, string) {
+	var goType, layout string
+	switch column.Type {
+	case separated_value.IntColumn:
+		goType = "int"
+	case separated_value.FloatColumn:
+		goType = "float64"
+	case separated_value.BoolColumn:
+		goType = "bool"
+	case separated_value.DateTimeColumn:
+		goType = "time.Time"
+		layout = column.Layout
+	default:
+		goType = "string"
+	}
+
+	if column.Nullable {
+		goType = "*" + goType
+	}
+
+	return goType, layout
+}
+
+// identifier Converts a file or column name such as "reward_id" into an exported Go identifier such as "RewardId".
+func identifier(name string) string {
+	var builder strings.Builder
+	isUpper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			isUpper = true
+			continue
+		}
+		if builder.Len() == 0 && unicode.IsDigit(r) {
+			builder.WriteString("X")
+		}
+		if isUpper {
+			builder.WriteRune(unicode.ToUpper(r))
+			isUpper = false
+		} else {
+			builder.WriteRune(r)
+		}
+	}
+
+	if builder.Len() == 0 {
+		return "X"
+	}
+
+	return builder.String()
+}
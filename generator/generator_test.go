@@ -0,0 +1,80 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stepupdream/golang-support-tool/separated_value"
+)
+
+func TestGenerateE(t *testing.T) {
+	var separatedValue separated_value.SeparatedValue
+	separatedValue.Init("csv", ".csv")
+
+	source, err := New(&separatedValue, "master").GenerateE("./test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []string{
+		"package master",
+		"\"time\"",
+		"type Item struct {",
+		"Id       int     `sv:\"id\"`",
+		"Name     string  `sv:\"name\"`",
+		"Rate     float64 `sv:\"rate\"`",
+		"IsActive bool    `sv:\"is_active\"`",
+		"type ItemReward struct {",
+		"ReleasedAt time.Time `sv:\"released_at,layout=2006-01-02\"`",
+		"Note       *string   `sv:\"note\"`",
+		"func LoadItemReward(separatedValue *separated_value.SeparatedValue, filePath string) ([]ItemReward, error) {",
+	}
+	for _, want := range tests {
+		if !strings.Contains(string(source), want) {
+			t.Errorf("GenerateE() does not contain %q\n%s", want, source)
+		}
+	}
+	if strings.Contains(string(source), "sv:\"#\"") {
+		t.Errorf("GenerateE() contains the excluded column\n%s", source)
+	}
+}
+
+func TestGenerateEShortRow(t *testing.T) {
+	separatedValue, err := separated_value.New(separated_value.WithFieldsPerRecord(-1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	directory := t.TempDir()
+	if err := os.WriteFile(filepath.Join(directory, "item.csv"), []byte("id,name,level\n1,a,3\n2,b\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	source, err := New(separatedValue, "master").GenerateE(directory)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Level int    `sv:\"level\"`"; !strings.Contains(string(source), want) {
+		t.Errorf("GenerateE() does not contain %q\n%s", want, source)
+	}
+}
+
+func TestIdentifier(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "reward_id", want: "RewardId"},
+		{name: "item-reward", want: "ItemReward"},
+		{name: "Level", want: "Level"},
+		{name: "1st_prize", want: "X1stPrize"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := identifier(tt.name); got != tt.want {
+				t.Errorf("identifier() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
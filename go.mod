@@ -1,10 +1,11 @@
 module github.com/stepupdream/golang-support-tool
 
-go 1.19
+go 1.20
 
 require (
 	github.com/cheggaaa/pb/v3 v3.1.0
 	github.com/pkg/errors v0.9.1
+	golang.org/x/text v0.3.8
 )
 
 require (
@@ -33,11 +33,13 @@ func TestDeleteCSV(t *testing.T) {
 			},
 		},
 	}
-	var separatedValue SeparatedValue
-	separatedValue.Init("csv", ".csv")
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := separatedValue.delete(tt.args.baseCSV, tt.args.editCSV, ""); !reflect.DeepEqual(got, tt.want) {
+			got, err := deleteRecords(recordMap(tt.args.baseCSV), recordMap(tt.args.editCSV), KeyDefinition{}, "", nil, nil)
+			if err != nil {
+				t.Fatalf("deleteCSV() error = %v", err)
+			}
+			if !reflect.DeepEqual(keyMap(got), tt.want) {
 				t.Errorf("deleteCSV() = %v, want %v", got, tt.want)
 			}
 		})
@@ -74,11 +76,13 @@ func TestInsertCSV(t *testing.T) {
 			},
 		},
 	}
-	var separatedValue SeparatedValue
-	separatedValue.Init("csv", ".csv")
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := separatedValue.insert(tt.args.baseCSV, tt.args.editCSV, ""); !reflect.DeepEqual(got, tt.want) {
+			got, err := insertRecords(recordMap(tt.args.baseCSV), recordMap(tt.args.editCSV), KeyDefinition{}, "", nil, nil)
+			if err != nil {
+				t.Fatalf("insertCSV() error = %v", err)
+			}
+			if !reflect.DeepEqual(keyMap(got), tt.want) {
 				t.Errorf("insertCSV() = %v, want %v", got, tt.want)
 			}
 		})
@@ -112,11 +116,13 @@ func TestUpdateCSV(t *testing.T) {
 			},
 		},
 	}
-	var separatedValue SeparatedValue
-	separatedValue.Init("csv", ".csv")
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := separatedValue.update(tt.args.baseCSV, tt.args.editCSV, ""); !reflect.DeepEqual(got, tt.want) {
+			got, err := updateRecords(recordMap(tt.args.baseCSV), recordMap(tt.args.editCSV), KeyDefinition{}, "", nil, nil)
+			if err != nil {
+				t.Fatalf("updateCSV() error = %v", err)
+			}
+			if !reflect.DeepEqual(keyMap(got), tt.want) {
 				t.Errorf("updateCSV() = %v, want %v", got, tt.want)
 			}
 		})
@@ -202,3 +208,55 @@ func TestLoadCsv(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertRows(t *testing.T) {
+	type args struct {
+		separatedValueMap map[Key]string
+		header            []string
+	}
+	tests := []struct {
+		name string
+		args args
+		want [][]string
+	}{
+		{
+			name: "ConvertRows",
+			args: args{
+				separatedValueMap: map[Key]string{
+					{Id: 2, Key: "id"}: "2", {Id: 2, Key: "name"}: "bbbb", {Id: 2, Key: "level"}: "20",
+					{Id: 1, Key: "id"}: "1", {Id: 1, Key: "name"}: "aaaa", {Id: 1, Key: "level"}: "10",
+				},
+				header: []string{"name", "id", "level"},
+			},
+			want: [][]string{
+				{"name", "id", "level"},
+				{"aaaa", "1", "10"},
+				{"bbbb", "2", "20"},
+			},
+		},
+		{
+			name: "ConvertRowsWithoutHeader",
+			args: args{
+				separatedValueMap: map[Key]string{
+					{Id: 2, Key: "id"}: "2", {Id: 2, Key: "name"}: "bbbb", {Id: 2, Key: "level"}: "20",
+					{Id: 1, Key: "id"}: "1", {Id: 1, Key: "name"}: "aaaa", {Id: 1, Key: "level"}: "10",
+				},
+				header: nil,
+			},
+			want: [][]string{
+				{"id", "level", "name"},
+				{"1", "10", "aaaa"},
+				{"2", "20", "bbbb"},
+			},
+		},
+	}
+	var separatedValue SeparatedValue
+	separatedValue.Init("csv", ".csv")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := separatedValue.ConvertRows(tt.args.separatedValueMap, tt.args.header); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ConvertRows() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
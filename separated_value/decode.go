@@ -0,0 +1,225 @@
+package separated_value
+
+import (
+	"encoding"
+	"log"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stepupdream/golang-support-tool/array"
+)
+
+// TagName The struct tag that maps a field to a column, e.g. `sv:"level"`.
+// Options follow the column name: `sv:"released_at,layout=2006-01-02"` or `sv:"tags,separator=|"`.
+// A field tagged `sv:"-"` is skipped, and a field without the tag maps to the column of the same name ignoring case.
+const TagName = "sv"
+
+// field A struct field mapped to a column.
+type field struct {
+	index     int
+	name      string
+	layout    string
+	separator string
+}
+
+// columnField The field decoded from the column at columnNumber.
+type columnField struct {
+	columnNumber int
+	columnName   string
+	field        field
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+var timeType = reflect.TypeOf(time.Time{})
+
+func LoadInto[T any](separatedValue *SeparatedValue, filePath string, filterNames []string) []T {
+	result, err := LoadIntoE[T](separatedValue, filePath, filterNames)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return result
+}
+
+// LoadIntoE Decodes the rows of the file into structs, in the order of the rows.
+// Comment rows and excluded columns (see LoadOptions) are skipped. When filterNames is not empty, only those columns are decoded.
+// A blank cell is decoded as the zero value (nil for a pointer field).
+func LoadIntoE[T any](separatedValue *SeparatedValue, filePath string, filterNames []string) ([]T, error) {
+	structType := reflect.TypeOf((*T)(nil)).Elem()
+	if structType.Kind() != reflect.Struct {
+		return nil, &Error{FilePath: filePath, Err: errors.Errorf("LoadInto requires a struct type, not %s", structType)}
+	}
+	fields := structFields(structType)
+
+	var result []T
+	var columnFields []columnField
+	isHeaderRead := false
+	err := separatedValue.Each(filePath, true, true, func(row []string, line int) error {
+		// The first line is the key.
+		if !isHeaderRead {
+			isHeaderRead = true
+			for columnNumber, name := range row {
+				if len(filterNames) != 0 && !array.StrContains(filterNames, name) {
+					continue
+				}
+				if field, ok := findField(fields, name); ok {
+					columnFields = append(columnFields, columnField{columnNumber: columnNumber, columnName: name, field: field})
+				}
+			}
+			return nil
+		}
+
+		var value T
+		structValue := reflect.ValueOf(&value).Elem()
+		for _, columnField := range columnFields {
+			text := cell(row, columnField.columnNumber)
+			if err := decodeValue(structValue.Field(columnField.field.index), text, columnField.field); err != nil {
+				return &DecodeError{FilePath: filePath, Row: line, Column: columnField.columnName, Value: text, Err: err}
+			}
+		}
+		result = append(result, value)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// structFields Returns the exported fields of the struct with their column names.
+func structFields(structType reflect.Type) []field {
+	var fields []field
+	for i := 0; i < structType.NumField(); i++ {
+		structField := structType.Field(i)
+		if !structField.IsExported() {
+			continue
+		}
+
+		tag, ok := structField.Tag.Lookup(TagName)
+		if tag == "-" {
+			continue
+		}
+
+		field := field{index: i, name: structField.Name, separator: ","}
+		if ok {
+			parts := strings.Split(tag, ",")
+			if parts[0] != "" {
+				field.name = parts[0]
+			}
+			for _, option := range parts[1:] {
+				key, value, _ := strings.Cut(option, "=")
+				switch key {
+				case "layout":
+					field.layout = value
+				case "separator":
+					field.separator = value
+				}
+			}
+		}
+		fields = append(fields, field)
+	}
+
+	return fields
+}
+
+// findField Finds the field of the column. The tagged name matches exactly, and an untagged field name ignores case.
+func findField(fields []field, name string) (field, bool) {
+	for _, field := range fields {
+		if field.name == name {
+			return field, true
+		}
+	}
+	for _, field := range fields {
+		if strings.EqualFold(field.name, name) {
+			return field, true
+		}
+	}
+
+	return field{}, false
+}
+
+func decodeValue(value reflect.Value, text string, field field) error {
+	if value.Kind() == reflect.Pointer {
+		if text == "" {
+			return nil
+		}
+		pointer := reflect.New(value.Type().Elem())
+		if err := decodeValue(pointer.Elem(), text, field); err != nil {
+			return err
+		}
+		value.Set(pointer)
+		return nil
+	}
+
+	// time.Time is also a TextUnmarshaler, but it is parsed with the layout of the field.
+	if value.Type() == timeType {
+		if text == "" {
+			return nil
+		}
+		layout := field.layout
+		if layout == "" {
+			layout = DefaultDateTimeLayout
+		}
+		parsed, err := time.Parse(layout, text)
+		if err != nil {
+			return err
+		}
+		value.Set(reflect.ValueOf(parsed))
+		return nil
+	}
+
+	if reflect.PointerTo(value.Type()).Implements(textUnmarshalerType) {
+		return value.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(text))
+	}
+
+	if text == "" {
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(text)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		number, err := strconv.ParseInt(text, 10, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetInt(number)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		number, err := strconv.ParseUint(text, 10, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetUint(number)
+	case reflect.Float32, reflect.Float64:
+		number, err := strconv.ParseFloat(text, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetFloat(number)
+	case reflect.Bool:
+		boolean, err := strconv.ParseBool(text)
+		if err != nil {
+			return err
+		}
+		value.SetBool(boolean)
+	case reflect.Slice:
+		parts := strings.Split(text, field.separator)
+		slice := reflect.MakeSlice(value.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := decodeValue(slice.Index(i), part, field); err != nil {
+				return err
+			}
+		}
+		value.Set(slice)
+	default:
+		return errors.Errorf("unsupported type %s", value.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package separated_value
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type decodeItem struct {
+	Id         int
+	Name       string
+	Level      int       `sv:"level"`
+	Rate       float64   `sv:"rate"`
+	IsLimited  bool      `sv:"is_limited"`
+	Tags       []string  `sv:"tags,separator=|"`
+	ReleasedAt time.Time `sv:"released_at,layout=2006-01-02"`
+	Memo       *string   `sv:"memo"`
+	Ignored    string    `sv:"-"`
+}
+
+func TestLoadIntoE(t *testing.T) {
+	var separatedValue SeparatedValue
+	separatedValue.Init("csv", ".csv")
+
+	got, err := LoadIntoE[decodeItem](&separatedValue, "test/decode/item.csv", []string{})
+	if err != nil {
+		t.Fatalf("LoadIntoE() error = %v", err)
+	}
+
+	memo := "note"
+	want := []decodeItem{
+		{
+			Id: 1, Name: "sword", Level: 10, Rate: 0.5, IsLimited: true, Tags: []string{"a", "b"},
+			ReleasedAt: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			Id: 3, Name: "shield", Level: 20, Rate: 1.5, IsLimited: false, Tags: []string{"c"},
+			ReleasedAt: time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC), Memo: &memo,
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadIntoE() = %+v, want %+v", got, want)
+	}
+
+	filtered, err := LoadIntoE[decodeItem](&separatedValue, "test/decode/item.csv", []string{"id", "level"})
+	if err != nil {
+		t.Fatalf("LoadIntoE() error = %v", err)
+	}
+	if filtered[1].Id != 3 || filtered[1].Level != 20 || filtered[1].Name != "" {
+		t.Errorf("LoadIntoE() with filterNames = %+v", filtered[1])
+	}
+}
+
+func TestLoadIntoEDecodeError(t *testing.T) {
+	var separatedValue SeparatedValue
+	separatedValue.Init("csv", ".csv")
+
+	var decodeError *DecodeError
+	_, err := LoadIntoE[decodeItem](&separatedValue, "test/decode/invalid.csv", []string{})
+	if !errors.As(err, &decodeError) || decodeError.Row != 2 || decodeError.Column != "level" || decodeError.Value != "high" {
+		t.Errorf("LoadIntoE() error = %v", err)
+	}
+}
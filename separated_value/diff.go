@@ -0,0 +1,187 @@
+package separated_value
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/stepupdream/golang-support-tool/array"
+)
+
+// Diff The IDs to insert, update and delete to turn one map into another. Each of them is sorted.
+type Diff struct {
+	InsertIds []int
+	UpdateIds []int
+	DeleteIds []int
+}
+
+// IsEmpty Returns true if the two maps were the same.
+func (diff Diff) IsEmpty() bool {
+	return len(diff.InsertIds) == 0 && len(diff.UpdateIds) == 0 && len(diff.DeleteIds) == 0
+}
+
+// Diff The inverse of LoadByDirectoryPath. Computes the minimal set of IDs that turn baseMap into desiredMap.
+// An ID is updated when any of its cells is different, including a cell that exists on only one side.
+func (separatedValue *SeparatedValue) Diff(baseMap map[Key]string, desiredMap map[Key]string) Diff {
+	baseIds := separatedValue.PluckId(baseMap)
+	desiredIds := separatedValue.PluckId(desiredMap)
+
+	baseRecords := groupById(baseMap)
+	desiredRecords := groupById(desiredMap)
+
+	var diff Diff
+	for _, id := range desiredIds {
+		if !array.IntContains(baseIds, id) {
+			diff.InsertIds = append(diff.InsertIds, id)
+			continue
+		}
+
+		if !isSameRecord(baseRecords[id], desiredRecords[id]) {
+			diff.UpdateIds = append(diff.UpdateIds, id)
+		}
+	}
+
+	for _, id := range baseIds {
+		if !array.IntContains(desiredIds, id) {
+			diff.DeleteIds = append(diff.DeleteIds, id)
+		}
+	}
+
+	return diff
+}
+
+func (separatedValue *SeparatedValue) WriteDiff(directoryPath string, fileName string, baseMap map[Key]string, desiredMap map[Key]string, header []string) Diff {
+	diff, err := separatedValue.WriteDiffE(directoryPath, fileName, baseMap, desiredMap, header)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return diff
+}
+
+// WriteDiffE Writes the difference between baseMap and desiredMap as insert/, update/ and delete/ files named fileName
+// under directoryPath, so that LoadByDirectoryPath(directoryPath, fileName, baseMap, ...) reproduces desiredMap.
+// header gives the column order. Columns not in header (or all of them when header is empty) follow "id" in name order.
+// For an operation without IDs, no file is written and the file left by an earlier call is removed.
+// An update only replaces the cells listed in the file, so an updated record cannot lose a column.
+// The records of one file also have to share the same columns, since a missing cell would be read back as blank.
+// In these cases an error is returned and nothing is written.
+func (separatedValue *SeparatedValue) WriteDiffE(directoryPath string, fileName string, baseMap map[Key]string, desiredMap map[Key]string, header []string) (Diff, error) {
+	diff := separatedValue.Diff(baseMap, desiredMap)
+
+	baseRecords := groupById(baseMap)
+	desiredRecords := groupById(desiredMap)
+	for _, id := range diff.UpdateIds {
+		for _, column := range sortedColumns(baseRecords[id]) {
+			if _, ok := desiredRecords[id][column]; !ok {
+				return diff, &Error{FilePath: filepath.Join(directoryPath, "update", fileName), Column: column,
+					Err: errors.Errorf("An update cannot remove a column : id %d", id)}
+			}
+		}
+	}
+
+	edits := []struct {
+		loadType string
+		ids      []int
+		records  map[int]map[string]string
+		source   map[Key]string
+	}{
+		{loadType: "insert", ids: diff.InsertIds, records: desiredRecords, source: desiredMap},
+		{loadType: "update", ids: diff.UpdateIds, records: desiredRecords, source: desiredMap},
+		// Every cell has to be listed, since delete only removes the cells in the file.
+		{loadType: "delete", ids: diff.DeleteIds, records: baseRecords, source: baseMap},
+	}
+
+	for _, edit := range edits {
+		if len(edit.ids) == 0 {
+			continue
+		}
+		first := edit.records[edit.ids[0]]
+		for _, id := range edit.ids[1:] {
+			if column, ok := differentColumn(first, edit.records[id]); ok {
+				return diff, &Error{FilePath: filepath.Join(directoryPath, edit.loadType, fileName), Column: column,
+					Err: errors.Errorf("The records of a file must have the same columns : id %d, %d", edit.ids[0], id)}
+			}
+		}
+	}
+
+	for _, edit := range edits {
+		loadTypePath := filepath.Join(directoryPath, edit.loadType)
+		if len(edit.ids) == 0 {
+			if err := os.Remove(filepath.Join(loadTypePath, fileName)); err != nil && !os.IsNotExist(err) {
+				return diff, &Error{FilePath: filepath.Join(loadTypePath, fileName), Err: errors.Wrap(err, "RemoveError")}
+			}
+			continue
+		}
+
+		if err := os.MkdirAll(loadTypePath, 0755); err != nil {
+			return diff, &Error{FilePath: loadTypePath, Err: errors.Wrap(err, "MkdirAllError")}
+		}
+
+		rows := convertRows(edit.source, edit.ids, header)
+		if err := separatedValue.NewFileE(filepath.Join(loadTypePath, fileName), rows); err != nil {
+			return diff, err
+		}
+	}
+
+	return diff, nil
+}
+
+func groupById(separatedValueMap map[Key]string) map[int]map[string]string {
+	result := make(map[int]map[string]string)
+	for mapKey, value := range separatedValueMap {
+		if _, ok := result[mapKey.Id]; !ok {
+			result[mapKey.Id] = make(map[string]string)
+		}
+		result[mapKey.Id][mapKey.Key] = value
+	}
+
+	return result
+}
+
+// sortedColumns Returns the columns of the record in name order, so that the reported column does not depend on the map order.
+func sortedColumns(record map[string]string) []string {
+	columns := make([]string, 0, len(record))
+	for column := range record {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	return columns
+}
+
+// differentColumn Returns the first column, in name order, that only one of the records has.
+func differentColumn(a map[string]string, b map[string]string) (string, bool) {
+	var columns []string
+	for column := range a {
+		if _, ok := b[column]; !ok {
+			columns = append(columns, column)
+		}
+	}
+	for column := range b {
+		if _, ok := a[column]; !ok {
+			columns = append(columns, column)
+		}
+	}
+	if len(columns) == 0 {
+		return "", false
+	}
+	sort.Strings(columns)
+
+	return columns[0], true
+}
+
+func isSameRecord(a map[string]string, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, value := range a {
+		if otherValue, ok := b[key]; !ok || otherValue != value {
+			return false
+		}
+	}
+
+	return true
+}
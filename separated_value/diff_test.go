@@ -0,0 +1,120 @@
+package separated_value
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestDiff(t *testing.T) {
+	baseMap := map[Key]string{
+		{Id: 1, Key: "id"}: "1", {Id: 1, Key: "name"}: "aaaa",
+		{Id: 2, Key: "id"}: "2", {Id: 2, Key: "name"}: "bbbb",
+		{Id: 3, Key: "id"}: "3", {Id: 3, Key: "name"}: "cccc",
+	}
+	desiredMap := map[Key]string{
+		{Id: 1, Key: "id"}: "1", {Id: 1, Key: "name"}: "aaaa",
+		{Id: 2, Key: "id"}: "2", {Id: 2, Key: "name"}: "eeee",
+		{Id: 4, Key: "id"}: "4", {Id: 4, Key: "name"}: "dddd",
+	}
+	var separatedValue SeparatedValue
+	separatedValue.Init("csv", ".csv")
+
+	want := Diff{InsertIds: []int{4}, UpdateIds: []int{2}, DeleteIds: []int{3}}
+	if got := separatedValue.Diff(baseMap, desiredMap); !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() = %v, want %v", got, want)
+	}
+
+	directoryPath := t.TempDir()
+	if _, err := separatedValue.WriteDiffE(directoryPath, "item.csv", baseMap, desiredMap, []string{"id", "name"}); err != nil {
+		t.Fatalf("WriteDiffE() error = %v", err)
+	}
+
+	got, err := separatedValue.LoadByDirectoryPathE(directoryPath, "item.csv", baseMap, []string{})
+	if err != nil {
+		t.Fatalf("LoadByDirectoryPathE() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, desiredMap) {
+		t.Errorf("LoadByDirectoryPathE() = %v, want %v", got, desiredMap)
+	}
+}
+
+func TestWriteDiffERemovedColumn(t *testing.T) {
+	baseMap := map[Key]string{
+		{Id: 1, Key: "id"}: "1", {Id: 1, Key: "name"}: "aaaa", {Id: 1, Key: "level"}: "3",
+	}
+	desiredMap := map[Key]string{
+		{Id: 1, Key: "id"}: "1", {Id: 1, Key: "name"}: "aaaa",
+	}
+	var separatedValue SeparatedValue
+	separatedValue.Init("csv", ".csv")
+
+	directoryPath := t.TempDir()
+	_, err := separatedValue.WriteDiffE(directoryPath, "item.csv", baseMap, desiredMap, []string{})
+	var separatedValueError *Error
+	if !errors.As(err, &separatedValueError) || separatedValueError.Column != "level" {
+		t.Errorf("WriteDiffE() error = %v, want an error of the column level", err)
+	}
+	if _, err := os.Stat(filepath.Join(directoryPath, "update")); !os.IsNotExist(err) {
+		t.Errorf("WriteDiffE() wrote the update directory, err = %v", err)
+	}
+}
+
+func TestWriteDiffEStaleFile(t *testing.T) {
+	baseMap := map[Key]string{
+		{Id: 1, Key: "id"}: "1", {Id: 1, Key: "name"}: "aaaa",
+		{Id: 2, Key: "id"}: "2", {Id: 2, Key: "name"}: "bbbb",
+	}
+	firstMap := map[Key]string{
+		{Id: 1, Key: "id"}: "1", {Id: 1, Key: "name"}: "aaaa",
+		{Id: 3, Key: "id"}: "3", {Id: 3, Key: "name"}: "cccc",
+	}
+	secondMap := map[Key]string{
+		{Id: 1, Key: "id"}: "1", {Id: 1, Key: "name"}: "eeee",
+		{Id: 2, Key: "id"}: "2", {Id: 2, Key: "name"}: "bbbb",
+	}
+	var separatedValue SeparatedValue
+	separatedValue.Init("csv", ".csv")
+
+	// The second call has nothing to insert or delete, so the files of the first call must not be applied again.
+	directoryPath := t.TempDir()
+	for _, desiredMap := range []map[Key]string{firstMap, secondMap} {
+		if _, err := separatedValue.WriteDiffE(directoryPath, "item.csv", baseMap, desiredMap, []string{"id", "name"}); err != nil {
+			t.Fatalf("WriteDiffE() error = %v", err)
+		}
+	}
+
+	got, err := separatedValue.LoadByDirectoryPathE(directoryPath, "item.csv", baseMap, []string{})
+	if err != nil {
+		t.Fatalf("LoadByDirectoryPathE() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, secondMap) {
+		t.Errorf("LoadByDirectoryPathE() = %v, want %v", got, secondMap)
+	}
+}
+
+func TestWriteDiffEDifferentColumns(t *testing.T) {
+	baseMap := map[Key]string{
+		{Id: 1, Key: "id"}: "1", {Id: 1, Key: "name"}: "aaaa",
+	}
+	desiredMap := map[Key]string{
+		{Id: 1, Key: "id"}: "1", {Id: 1, Key: "name"}: "aaaa",
+		{Id: 2, Key: "id"}: "2", {Id: 2, Key: "name"}: "bbbb",
+		{Id: 3, Key: "id"}: "3", {Id: 3, Key: "name"}: "cccc", {Id: 3, Key: "level"}: "5",
+	}
+	var separatedValue SeparatedValue
+	separatedValue.Init("csv", ".csv")
+
+	directoryPath := t.TempDir()
+	_, err := separatedValue.WriteDiffE(directoryPath, "item.csv", baseMap, desiredMap, []string{})
+	var separatedValueError *Error
+	if !errors.As(err, &separatedValueError) || separatedValueError.Column != "level" {
+		t.Errorf("WriteDiffE() error = %v, want an error of the column level", err)
+	}
+	if _, err := os.Stat(filepath.Join(directoryPath, "insert")); !os.IsNotExist(err) {
+		t.Errorf("WriteDiffE() wrote the insert directory, err = %v", err)
+	}
+}
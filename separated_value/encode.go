@@ -0,0 +1,127 @@
+package separated_value
+
+import (
+	"encoding"
+	"log"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Formatter Converts the value of a field into a cell, in place of the default conversion.
+type Formatter func(value interface{}) (string, error)
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+func NewFileFromStructs[T any](separatedValue *SeparatedValue, path string, values []T, formatters map[string]Formatter) {
+	err := NewFileFromStructsE(separatedValue, path, values, formatters)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// NewFileFromStructsE The mirror of LoadIntoE. Writes a header row and one row per struct with NewFileE.
+func NewFileFromStructsE[T any](separatedValue *SeparatedValue, path string, values []T, formatters map[string]Formatter) error {
+	rows, err := EncodeRows(values, formatters)
+	if err != nil {
+		var encodeError *Error
+		if errors.As(err, &encodeError) {
+			encodeError.FilePath = path
+		}
+		return err
+	}
+
+	return separatedValue.NewFileE(path, rows)
+}
+
+// EncodeRows Converts the structs into a header row and data rows.
+// The columns are in the order of the struct fields, named and formatted with the same tags as LoadInto.
+// formatters are keyed by column name.
+func EncodeRows[T any](values []T, formatters map[string]Formatter) ([][]string, error) {
+	structType := reflect.TypeOf((*T)(nil)).Elem()
+	if structType.Kind() != reflect.Struct {
+		return nil, &Error{Err: errors.Errorf("EncodeRows requires a struct type, not %s", structType)}
+	}
+	fields := structFields(structType)
+
+	header := make([]string, 0, len(fields))
+	for _, field := range fields {
+		header = append(header, field.name)
+	}
+
+	rows := [][]string{header}
+	for index, value := range values {
+		structValue := reflect.ValueOf(value)
+		row := make([]string, 0, len(fields))
+		for _, field := range fields {
+			fieldValue := structValue.Field(field.index)
+
+			var text string
+			var err error
+			if formatter, ok := formatters[field.name]; ok {
+				text, err = formatter(fieldValue.Interface())
+			} else {
+				text, err = encodeValue(fieldValue, field)
+			}
+			if err != nil {
+				// The header is the first line, so the row of values[index] is index + 2.
+				return nil, &Error{Row: index + 2, Column: field.name, Err: err}
+			}
+			row = append(row, text)
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func encodeValue(value reflect.Value, field field) (string, error) {
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return "", nil
+		}
+		return encodeValue(value.Elem(), field)
+	}
+
+	// time.Time is also a TextMarshaler, but it is formatted with the layout of the field.
+	if value.Type() == timeType {
+		layout := field.layout
+		if layout == "" {
+			layout = DefaultDateTimeLayout
+		}
+		return value.Interface().(time.Time).Format(layout), nil
+	}
+
+	if value.Type().Implements(textMarshalerType) {
+		text, err := value.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		return value.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(value.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'f', -1, value.Type().Bits()), nil
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool()), nil
+	case reflect.Slice:
+		parts := make([]string, 0, value.Len())
+		for i := 0; i < value.Len(); i++ {
+			part, err := encodeValue(value.Index(i), field)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, part)
+		}
+		return strings.Join(parts, field.separator), nil
+	}
+
+	return "", errors.Errorf("unsupported type %s", value.Type())
+}
@@ -0,0 +1,61 @@
+package separated_value
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestEncodeRows(t *testing.T) {
+	memo := "note"
+	values := []decodeItem{
+		{
+			Id: 1, Name: "sword", Level: 10, Rate: 0.5, IsLimited: true, Tags: []string{"a", "b"},
+			ReleasedAt: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			Id: 3, Name: "shield", Level: 20, Rate: 1.5, IsLimited: false, Tags: []string{"c"},
+			ReleasedAt: time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC), Memo: &memo,
+		},
+	}
+	formatters := map[string]Formatter{
+		"level": func(value interface{}) (string, error) {
+			return "Lv" + strconv.Itoa(value.(int)), nil
+		},
+	}
+
+	got, err := EncodeRows(values, formatters)
+	if err != nil {
+		t.Fatalf("EncodeRows() error = %v", err)
+	}
+	want := [][]string{
+		{"Id", "Name", "level", "rate", "is_limited", "tags", "released_at", "memo"},
+		{"1", "sword", "Lv10", "0.5", "true", "a|b", "2023-01-01", ""},
+		{"3", "shield", "Lv20", "1.5", "false", "c", "2023-02-01", "note"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("EncodeRows() = %v, want %v", got, want)
+	}
+}
+
+func TestNewFileFromStructsE(t *testing.T) {
+	var separatedValue SeparatedValue
+	separatedValue.Init("tsv", ".tsv")
+
+	values := []decodeItem{
+		{Id: 1, Name: "sword", Level: 10, Tags: []string{"a"}, ReleasedAt: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	path := t.TempDir() + "/item.tsv"
+	if err := NewFileFromStructsE(&separatedValue, path, values, nil); err != nil {
+		t.Fatalf("NewFileFromStructsE() error = %v", err)
+	}
+
+	got, err := LoadIntoE[decodeItem](&separatedValue, path, []string{})
+	if err != nil {
+		t.Fatalf("LoadIntoE() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, values) {
+		t.Errorf("LoadIntoE() = %+v, want %+v", got, values)
+	}
+}
@@ -0,0 +1,211 @@
+package separated_value
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"log"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	textEncoding "golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// Encoding The character encoding of a file. The zero value is UTF-8.
+type Encoding string
+
+const (
+	// AutoDetect Detects the encoding when reading, with DetectEncodingE. It cannot be used for writing.
+	AutoDetect Encoding = "auto"
+	UTF8       Encoding = "utf-8"
+	ShiftJIS   Encoding = "shift_jis"
+	UTF16LE    Encoding = "utf-16le"
+	UTF16BE    Encoding = "utf-16be"
+)
+
+// detectionSize The number of bytes DetectEncoding is given when reading a file.
+const detectionSize = 4096
+
+var (
+	utf8BOM    = []byte{0xEF, 0xBB, 0xBF}
+	utf16LEBOM = []byte{0xFF, 0xFE}
+	utf16BEBOM = []byte{0xFE, 0xFF}
+)
+
+// WithReadEncoding The encoding of the files to read. The default is UTF8. With AutoDetect, the encoding of every file is
+// detected from its start, and a file that is neither UTF-8, UTF-16 nor Shift_JIS is an error.
+func WithReadEncoding(encoding Encoding) Option {
+	return func(separatedValue *SeparatedValue) {
+		separatedValue.readEncoding = encoding
+	}
+}
+
+// WithWriteEncoding The encoding of the files to write. The default is UTF8.
+// A BOM is written for UTF-8 and UTF-16 (see WithBOMWriting), never for Shift_JIS.
+func WithWriteEncoding(encoding Encoding) Option {
+	return func(separatedValue *SeparatedValue) {
+		separatedValue.writeEncoding = encoding
+	}
+}
+
+func DetectEncoding(sample []byte) Encoding {
+	encoding, err := DetectEncodingE(sample)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return encoding
+}
+
+// DetectEncodingE Guesses the encoding from the start of a file. A BOM decides it. Otherwise, text with many NUL bytes
+// at odd (or even) positions is UTF-16LE (or BE), text that is valid UTF-8 is UTF-8, and text that is valid Shift_JIS
+// is Shift_JIS, which is what Japanese Excel saves as "CSV". Anything else is an error.
+func DetectEncodingE(sample []byte) (Encoding, error) {
+	switch {
+	case bytes.HasPrefix(sample, utf8BOM):
+		return UTF8, nil
+	case bytes.HasPrefix(sample, utf16LEBOM):
+		return UTF16LE, nil
+	case bytes.HasPrefix(sample, utf16BEBOM):
+		return UTF16BE, nil
+	}
+
+	evenZeros, oddZeros := 0, 0
+	for index, character := range sample {
+		if character != 0 {
+			continue
+		}
+		if index%2 == 0 {
+			evenZeros++
+		} else {
+			oddZeros++
+		}
+	}
+	// ASCII text in UTF-16 has a NUL byte in every character.
+	if len(sample) >= 2 && oddZeros > len(sample)/4 && oddZeros > evenZeros*2 {
+		return UTF16LE, nil
+	}
+	if len(sample) >= 2 && evenZeros > len(sample)/4 && evenZeros > oddZeros*2 {
+		return UTF16BE, nil
+	}
+
+	// The sample may end in the middle of a character.
+	for index := len(sample) - 1; index >= 0 && index >= len(sample)-utf8.UTFMax; index-- {
+		if utf8.RuneStart(sample[index]) {
+			if !utf8.FullRune(sample[index:]) {
+				sample = sample[:index]
+			}
+			break
+		}
+	}
+	if utf8.Valid(sample) {
+		return UTF8, nil
+	}
+
+	if isShiftJIS(sample) {
+		return ShiftJIS, nil
+	}
+
+	return "", errors.New("The encoding is neither UTF-8, UTF-16 nor Shift_JIS")
+}
+
+// isShiftJIS Returns true if the sample decodes as Shift_JIS without an invalid byte.
+// The sample may end in the middle of a character, which is left undecoded.
+func isShiftJIS(sample []byte) bool {
+	decoded := make([]byte, len(sample)*utf8.UTFMax)
+	decodedSize, _, err := japanese.ShiftJIS.NewDecoder().Transform(decoded, sample, false)
+	if err != nil && err != transform.ErrShortSrc {
+		return false
+	}
+
+	return !bytes.ContainsRune(decoded[:decodedSize], utf8.RuneError)
+}
+
+func (encoding Encoding) isValid() bool {
+	switch encoding {
+	case "", AutoDetect, UTF8, ShiftJIS, UTF16LE, UTF16BE:
+		return true
+	}
+
+	return false
+}
+
+func (encoding Encoding) textEncoding() textEncoding.Encoding {
+	switch encoding {
+	case ShiftJIS:
+		return japanese.ShiftJIS
+	case UTF16LE:
+		return unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM)
+	case UTF16BE:
+		return unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM)
+	}
+
+	return nil
+}
+
+// decode Returns a reader of the content of the file in UTF-8. The BOM of UTF-16 becomes the BOM of UTF-8,
+// which is left to the caller so that WithBOMStripping applies to both.
+func (separatedValue *SeparatedValue) decode(reader *bufio.Reader) (*bufio.Reader, error) {
+	encoding := separatedValue.readEncoding
+	if encoding == AutoDetect {
+		// Peek returns what it could read with io.EOF for a small file.
+		sample, err := reader.Peek(detectionSize)
+		if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+			return nil, errors.Wrap(err, "DetectEncodingError")
+		}
+		encoding, err = DetectEncodingE(sample)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	characterEncoding := encoding.textEncoding()
+	if characterEncoding == nil {
+		return reader, nil
+	}
+
+	return bufio.NewReader(transform.NewReader(reader, characterEncoding.NewDecoder())), nil
+}
+
+// encode Returns a writer that encodes UTF-8 into the encoding of the written files, after writing the BOM.
+// The writer must be closed to flush the last characters.
+func (separatedValue *SeparatedValue) encode(writer io.Writer) (io.WriteCloser, error) {
+	encoding := separatedValue.writeEncoding
+	if encoding == "" {
+		encoding = UTF8
+	}
+
+	if !separatedValue.isOmitBOM {
+		var bom []byte
+		switch encoding {
+		case UTF8:
+			bom = utf8BOM
+		case UTF16LE:
+			bom = utf16LEBOM
+		case UTF16BE:
+			bom = utf16BEBOM
+		}
+		if _, err := writer.Write(bom); err != nil {
+			return nil, err
+		}
+	}
+
+	characterEncoding := encoding.textEncoding()
+	if characterEncoding == nil {
+		return nopWriteCloser{writer}, nil
+	}
+
+	// A character that the encoding cannot represent is an error.
+	return transform.NewWriter(writer, characterEncoding.NewEncoder()), nil
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
@@ -0,0 +1,137 @@
+package separated_value
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDetectEncoding(t *testing.T) {
+	tests := []struct {
+		name    string
+		sample  []byte
+		want    Encoding
+		wantErr bool
+	}{
+		{name: "empty", sample: []byte{}, want: UTF8},
+		{name: "ascii", sample: []byte("id,name\n1,sword\n"), want: UTF8},
+		{name: "utf-8", sample: []byte("id,name\n1,剣\n"), want: UTF8},
+		{name: "utf-8 cut in a character", sample: []byte("id,name\n1,剣")[:12], want: UTF8},
+		{name: "utf-8 bom", sample: []byte("\xEF\xBB\xBFid"), want: UTF8},
+		{name: "shift_jis", sample: []byte("id,name\n1,\x8C\x95\n"), want: ShiftJIS},
+		{name: "utf-16le bom", sample: []byte("\xFF\xFEi\x00d\x00"), want: UTF16LE},
+		{name: "utf-16be bom", sample: []byte("\xFE\xFF\x00i\x00d"), want: UTF16BE},
+		{name: "utf-16le", sample: []byte("i\x00d\x00\t\x00n\x00"), want: UTF16LE},
+		{name: "utf-16be", sample: []byte("\x00i\x00d\x00\t\x00n"), want: UTF16BE},
+		{name: "shift_jis cut in a character", sample: []byte("id,name\n1,\x8C\x95\x8C"), want: ShiftJIS},
+		{name: "neither", sample: []byte("id,name\n1,\x8C\x7F\n"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DetectEncodingE(tt.sample)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DetectEncodingE() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("DetectEncodingE() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodingNewFileAndLoad(t *testing.T) {
+	rows := [][]string{{"id", "name"}, {"1", "剣"}, {"2", "ｼｰﾙﾄﾞ"}}
+
+	tests := []struct {
+		name      string
+		encoding  Encoding
+		delimiter rune
+		wantHead  []byte
+	}{
+		{name: "utf-8", encoding: UTF8, delimiter: ',', wantHead: []byte("\xEF\xBB\xBFid,")},
+		{name: "shift_jis", encoding: ShiftJIS, delimiter: ',', wantHead: []byte("id,")},
+		{name: "utf-16le", encoding: UTF16LE, delimiter: '\t', wantHead: []byte("\xFF\xFEi\x00d\x00\t\x00")},
+		{name: "utf-16be", encoding: UTF16BE, delimiter: '\t', wantHead: []byte("\xFE\xFF\x00i\x00d\x00\t")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer, err := New(WithDelimiter(tt.delimiter), WithWriteEncoding(tt.encoding))
+			if err != nil {
+				t.Fatal(err)
+			}
+			path := filepath.Join(t.TempDir(), "item"+writer.GetExtension())
+			if err := writer.NewFileE(path, rows); err != nil {
+				t.Fatal(err)
+			}
+
+			content, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(content[:len(tt.wantHead)], tt.wantHead) {
+				t.Errorf("NewFileE() = %q, want the head %q", content, tt.wantHead)
+			}
+
+			for _, readEncoding := range []Encoding{AutoDetect, tt.encoding} {
+				reader, err := New(WithDelimiter(tt.delimiter), WithReadEncoding(readEncoding))
+				if err != nil {
+					t.Fatal(err)
+				}
+				got, err := reader.LoadE(path, true, true)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(got, rows) {
+					t.Errorf("LoadE() with %q = %q, want %q", readEncoding, got, rows)
+				}
+			}
+		})
+	}
+
+	t.Run("not representable", func(t *testing.T) {
+		writer, err := New(WithWriteEncoding(ShiftJIS))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := writer.NewFileE(filepath.Join(t.TempDir(), "item.csv"), [][]string{{"name"}, {"😀"}}); err == nil {
+			t.Error("NewFileE() error = nil")
+		}
+	})
+
+	t.Run("unknown encoding", func(t *testing.T) {
+		if _, err := New(WithReadEncoding("latin-1")); err == nil {
+			t.Error("New() error = nil")
+		}
+		if _, err := New(WithWriteEncoding(AutoDetect)); err == nil {
+			t.Error("New() error = nil")
+		}
+	})
+
+	t.Run("invalid utf-8", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "item.csv")
+		if err := os.WriteFile(path, []byte("id,name\n1,\xE5\x89\xA3\xFF\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		// UTF-8 is read as is unless detection is asked for.
+		var separatedValue SeparatedValue
+		separatedValue.Init("csv", ".csv")
+		got, err := separatedValue.LoadE(path, true, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := [][]string{{"id", "name"}, {"1", "\xE5\x89\xA3\xFF"}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("LoadE() = %q, want %q", got, want)
+		}
+
+		reader, err := New(WithReadEncoding(AutoDetect))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := reader.LoadE(path, true, true); err == nil {
+			t.Error("LoadE() with AutoDetect error = nil")
+		}
+	})
+}
@@ -0,0 +1,197 @@
+package separated_value
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Error Error with the position in the separated value file where the problem occurred.
+// Row is the line number in the file (1-based), and is 0 when it cannot be specified.
+type Error struct {
+	FilePath string
+	Row      int
+	Column   string
+	Err      error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error() + position(e.FilePath, e.Row, e.Column)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// DuplicateIDError The same ID appears more than once in a file, or in the files of a version directory.
+// For a version directory, FilePath and Row are where the ID appears the second time.
+// RecordId is set instead of Id when the key is not the integer "id" column.
+type DuplicateIDError struct {
+	FilePath string
+	Row      int
+	Column   string
+	Id       int
+	RecordId string
+}
+
+func (e *DuplicateIDError) Error() string {
+	return "ID is not unique : id " + formatId(e.Id, e.RecordId) + position(e.FilePath, e.Row, e.Column)
+}
+
+// InvalidIDError The value of an id column cannot be parsed as the type of the key.
+type InvalidIDError struct {
+	FilePath string
+	Row      int
+	Column   string
+	Value    string
+}
+
+func (e *InvalidIDError) Error() string {
+	return "ID is not a valid value : " + strconv.Quote(e.Value) + position(e.FilePath, e.Row, e.Column)
+}
+
+// BlankCellError A cell is empty. It is impossible to determine if the designer forgot to enter the information.
+// RecordId is set instead of Id when the key is not the integer "id" column.
+type BlankCellError struct {
+	FilePath string
+	Row      int
+	Column   string
+	Id       int
+	RecordId string
+}
+
+func (e *BlankCellError) Error() string {
+	return "Blank space is prohibited because it is impossible to determine if you forgot to enter the information. : id " +
+		formatId(e.Id, e.RecordId) + position(e.FilePath, e.Row, e.Column)
+}
+
+// SchemaError A cell does not match the type, range or pattern of its column in the schema.
+// Reason is what is wrong with the value, such as "not an int" or "greater than 100".
+type SchemaError struct {
+	FilePath string
+	Row      int
+	Column   string
+	Value    string
+	Reason   string
+}
+
+func (e *SchemaError) Error() string {
+	return "The value does not match the schema : " + strconv.Quote(e.Value) + " is " + e.Reason + position(e.FilePath, e.Row, e.Column)
+}
+
+// DecodeError A cell cannot be converted into the type of the struct field.
+type DecodeError struct {
+	FilePath string
+	Row      int
+	Column   string
+	Value    string
+	Err      error
+}
+
+func (e *DecodeError) Error() string {
+	return "The value cannot be decoded : " + strconv.Quote(e.Value) + " : " + e.Err.Error() + position(e.FilePath, e.Row, e.Column)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// DanglingReferenceError A value refers to a record that does not exist in the referenced table.
+// Version is the version directory that set the value, and is empty when it comes from the base file.
+type DanglingReferenceError struct {
+	FilePath   string
+	Version    string
+	Row        int
+	Column     string
+	Id         int
+	Value      string
+	ForeignKey ForeignKey
+}
+
+func (e *DanglingReferenceError) Error() string {
+	message := "The referenced record does not exist : " + e.ForeignKey.String() + " : id " + strconv.Itoa(e.Id) + " value " + strconv.Quote(e.Value)
+	if e.Version != "" {
+		message += " version : " + e.Version
+	}
+
+	return message + position(e.FilePath, e.Row, e.Column)
+}
+
+// DeletedReferenceError A record deleted by a version is still referenced by another record.
+// DeleteFilePath and Version are where the referenced record was deleted, FilePath and Row are where the reference was set.
+type DeletedReferenceError struct {
+	FilePath       string
+	Row            int
+	Column         string
+	Id             int
+	Value          string
+	DeleteFilePath string
+	Version        string
+	ForeignKey     ForeignKey
+}
+
+func (e *DeletedReferenceError) Error() string {
+	return "The deleted record is still referenced : " + e.ForeignKey.String() + " : id " + strconv.Itoa(e.Id) + " value " + strconv.Quote(e.Value) +
+		" deleted in " + e.Version + " " + e.DeleteFilePath + position(e.FilePath, e.Row, e.Column)
+}
+
+// MissingIDColumnError The header row does not have an id column.
+type MissingIDColumnError struct {
+	FilePath string
+	Column   string
+}
+
+func (e *MissingIDColumnError) Error() string {
+	return "Separated value without ID column cannot be read" + position(e.FilePath, 0, e.Column)
+}
+
+// UnknownIDError An update or delete targets an ID that does not exist in the base data.
+// RecordId is set instead of Id when the key is not the integer "id" column.
+type UnknownIDError struct {
+	FilePath  string
+	Row       int
+	Column    string
+	Id        int
+	RecordId  string
+	Operation string
+}
+
+func (e *UnknownIDError) Error() string {
+	return "Tried to " + e.Operation + " a non-existent ID : id " + formatId(e.Id, e.RecordId) + position(e.FilePath, e.Row, e.Column)
+}
+
+// ExistingIDError An insert targets an ID that already exists in the base data.
+// RecordId is set instead of Id when the key is not the integer "id" column.
+type ExistingIDError struct {
+	FilePath string
+	Row      int
+	Column   string
+	Id       int
+	RecordId string
+}
+
+func (e *ExistingIDError) Error() string {
+	return "Tried to do an insert on an existing ID : id " + formatId(e.Id, e.RecordId) + position(e.FilePath, e.Row, e.Column)
+}
+
+func formatId(id int, recordId string) string {
+	if recordId != "" {
+		return strings.ReplaceAll(recordId, RecordIdSeparator, ",")
+	}
+
+	return strconv.Itoa(id)
+}
+
+func position(filePath string, row int, column string) string {
+	var result string
+	if filePath != "" {
+		result += " : " + filePath
+	}
+	if row != 0 {
+		result += " rowNumber : " + strconv.Itoa(row)
+	}
+	if column != "" {
+		result += " column : " + column
+	}
+
+	return result
+}
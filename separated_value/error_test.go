@@ -0,0 +1,79 @@
+package separated_value
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestLoadMapEError(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		check    func(err error) bool
+	}{
+		{
+			name:     "DuplicateIDError",
+			filePath: "test/duplicate_id.csv",
+			check: func(err error) bool {
+				var target *DuplicateIDError
+				return errors.As(err, &target) && target.Id == 1 && target.Row == 3 && target.Column == "id"
+			},
+		},
+		{
+			name:     "BlankCellError",
+			filePath: "test/blank.csv",
+			check: func(err error) bool {
+				var target *BlankCellError
+				return errors.As(err, &target) && target.Id == 2 && target.Row == 3 && target.Column == "name"
+			},
+		},
+		{
+			name:     "MissingIDColumnError",
+			filePath: "test/no_id.csv",
+			check: func(err error) bool {
+				var target *MissingIDColumnError
+				return errors.As(err, &target) && target.FilePath == "test/no_id.csv"
+			},
+		},
+		{
+			name:     "Error",
+			filePath: "test",
+			check: func(err error) bool {
+				var target *Error
+				return errors.As(err, &target) && target.FilePath == "test"
+			},
+		},
+	}
+	var separatedValue SeparatedValue
+	separatedValue.Init("csv", ".csv")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := separatedValue.LoadMapE(tt.filePath, []string{}, true); !tt.check(err) {
+				t.Errorf("LoadMapE() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestEditError(t *testing.T) {
+	baseMap := map[Key]string{
+		{Id: 1, Key: "id"}: "1", {Id: 1, Key: "name"}: "aaaa",
+	}
+	editMap := map[Key]string{
+		{Id: 2, Key: "id"}: "2", {Id: 2, Key: "name"}: "bbbb",
+	}
+
+	var unknownIDError *UnknownIDError
+	if _, err := updateRecords(recordMap(baseMap), recordMap(editMap), KeyDefinition{}, "update.csv", nil, nil); !errors.As(err, &unknownIDError) || unknownIDError.Operation != "update" || unknownIDError.Id != 2 {
+		t.Errorf("updateRecords() error = %v", err)
+	}
+	if _, err := deleteRecords(recordMap(baseMap), recordMap(editMap), KeyDefinition{}, "delete.csv", nil, nil); !errors.As(err, &unknownIDError) || unknownIDError.Operation != "delete" || unknownIDError.Id != 2 {
+		t.Errorf("deleteRecords() error = %v", err)
+	}
+
+	var existingIDError *ExistingIDError
+	if _, err := insertRecords(recordMap(baseMap), recordMap(baseMap), KeyDefinition{}, "insert.csv", nil, nil); !errors.As(err, &existingIDError) || existingIDError.Id != 1 {
+		t.Errorf("insertRecords() error = %v", err)
+	}
+}
@@ -0,0 +1,202 @@
+package separated_value
+
+import (
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	supportFile "github.com/stepupdream/golang-support-tool/file"
+)
+
+// ForeignKey A column of a table that refers to a column (usually id) of another table.
+// A table is the file name without the extension, e.g. "item" for item.csv.
+type ForeignKey struct {
+	Table            string
+	Column           string
+	ReferencedTable  string
+	ReferencedColumn string
+}
+
+// ParseForeignKey Parses a rule written as "item.reward_id -> reward.id".
+func ParseForeignKey(rule string) (ForeignKey, error) {
+	from, to, ok := strings.Cut(rule, "->")
+	if !ok {
+		return ForeignKey{}, errors.Errorf("Invalid foreign key rule : %s", rule)
+	}
+
+	table, column, ok := strings.Cut(strings.TrimSpace(from), ".")
+	if !ok || table == "" || column == "" {
+		return ForeignKey{}, errors.Errorf("Invalid foreign key rule : %s", rule)
+	}
+	referencedTable, referencedColumn, ok := strings.Cut(strings.TrimSpace(to), ".")
+	if !ok || referencedTable == "" || referencedColumn == "" {
+		return ForeignKey{}, errors.Errorf("Invalid foreign key rule : %s", rule)
+	}
+
+	return ForeignKey{Table: table, Column: column, ReferencedTable: referencedTable, ReferencedColumn: referencedColumn}, nil
+}
+
+func (foreignKey ForeignKey) String() string {
+	return foreignKey.Table + "." + foreignKey.Column + " -> " + foreignKey.ReferencedTable + "." + foreignKey.ReferencedColumn
+}
+
+// CheckForeignKeys Checks the foreign keys on the data resolved by Resolver, and reports every dangling reference at once.
+// The returned error is ValidationErrors of DanglingReferenceError. Blank cells are not references.
+func CheckForeignKeys(resolved map[string]*Resolved, foreignKeys []ForeignKey) error {
+	tables := make(map[string]*Resolved)
+	for fileName, resolvedFile := range resolved {
+		tables[supportFile.GetNameWithoutExtension(fileName)] = resolvedFile
+	}
+
+	collector := &collector{}
+	for _, foreignKey := range foreignKeys {
+		table, ok := tables[foreignKey.Table]
+		if !ok {
+			_ = collector.add(&Error{Err: errors.New("The table of the foreign key could not be found : " + foreignKey.String())})
+			continue
+		}
+		referencedTable, ok := tables[foreignKey.ReferencedTable]
+		if !ok {
+			_ = collector.add(&Error{Err: errors.New("The referenced table of the foreign key could not be found : " + foreignKey.String())})
+			continue
+		}
+
+		referencedValues := map[string]bool{}
+		for mapKey, value := range referencedTable.Map {
+			if mapKey.Key == foreignKey.ReferencedColumn {
+				referencedValues[value] = true
+			}
+		}
+
+		for _, mapKey := range sortedKeys(table.Map, foreignKey.Column) {
+			value := table.Map[mapKey]
+			if value == "" || referencedValues[value] {
+				continue
+			}
+
+			origin := table.Provenance[mapKey]
+			_ = collector.add(&DanglingReferenceError{
+				FilePath:   origin.FilePath,
+				Version:    origin.Version,
+				Row:        origin.Row,
+				Column:     mapKey.Key,
+				Id:         mapKey.Id,
+				Value:      value,
+				ForeignKey: foreignKey,
+			})
+		}
+	}
+
+	return collector.err()
+}
+
+// sortedKeys Returns the keys of the column in the order of id.
+func sortedKeys(separatedValueMap map[Key]string, column string) []Key {
+	var keys []Key
+	for mapKey := range separatedValueMap {
+		if mapKey.Key == column {
+			keys = append(keys, mapKey)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].Id < keys[j].Id
+	})
+
+	return keys
+}
+
+// deletion Where a referenced value was deleted.
+type deletion struct {
+	filePath string
+	version  string
+}
+
+func (resolver *Resolver) CheckDeletes(start string, end string, filterNames []string, foreignKeys []ForeignKey) {
+	err := resolver.CheckDeletesE(start, end, filterNames, foreignKeys)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// CheckDeletesE Replays the versions like ResolveE, and reports every record that still refers to a record
+// deleted by a version, either in the same version or in a later one. A record inserted again is no longer deleted.
+// The returned error is ValidationErrors of DeletedReferenceError.
+func (resolver *Resolver) CheckDeletesE(start string, end string, filterNames []string, foreignKeys []ForeignKey) error {
+	collector := &collector{}
+	deletions := make([]map[string]deletion, len(foreignKeys))
+	previousValues := make([]map[string]bool, len(foreignKeys))
+	reported := map[string]bool{}
+
+	_, err := resolver.resolve(start, end, filterNames, func(version string, result map[string]*Resolved) error {
+		tables := make(map[string]*Resolved)
+		for fileName, resolvedFile := range result {
+			tables[supportFile.GetNameWithoutExtension(fileName)] = resolvedFile
+		}
+
+		for index, foreignKey := range foreignKeys {
+			values := map[string]bool{}
+			referencedTable, ok := tables[foreignKey.ReferencedTable]
+			if ok {
+				for mapKey, value := range referencedTable.Map {
+					if mapKey.Key == foreignKey.ReferencedColumn {
+						values[value] = true
+					}
+				}
+			}
+
+			if deletions[index] == nil {
+				deletions[index] = make(map[string]deletion)
+			}
+			if version != "" {
+				for value := range previousValues[index] {
+					if !values[value] {
+						filePath, err := resolver.editFilePath(version, "delete", referencedTable.FileName)
+						if err != nil {
+							return err
+						}
+						deletions[index][value] = deletion{filePath: filePath, version: version}
+					}
+				}
+			}
+			for value := range values {
+				delete(deletions[index], value)
+			}
+			previousValues[index] = values
+
+			table, ok := tables[foreignKey.Table]
+			if !ok {
+				continue
+			}
+			for _, mapKey := range sortedKeys(table.Map, foreignKey.Column) {
+				value := table.Map[mapKey]
+				deletion, ok := deletions[index][value]
+				reportKey := strconv.Itoa(index) + "/" + strconv.Itoa(mapKey.Id) + "/" + value
+				if !ok || reported[reportKey] {
+					continue
+				}
+				reported[reportKey] = true
+
+				origin := table.Provenance[mapKey]
+				_ = collector.add(&DeletedReferenceError{
+					FilePath:       origin.FilePath,
+					Row:            origin.Row,
+					Column:         mapKey.Key,
+					Id:             mapKey.Id,
+					Value:          value,
+					DeleteFilePath: deletion.filePath,
+					Version:        deletion.version,
+					ForeignKey:     foreignKey,
+				})
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return collector.err()
+}
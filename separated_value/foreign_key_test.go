@@ -0,0 +1,64 @@
+package separated_value
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseForeignKey(t *testing.T) {
+	got, err := ParseForeignKey("item.reward_id -> reward.id")
+	if err != nil {
+		t.Fatalf("ParseForeignKey() error = %v", err)
+	}
+	want := ForeignKey{Table: "item", Column: "reward_id", ReferencedTable: "reward", ReferencedColumn: "id"}
+	if got != want {
+		t.Errorf("ParseForeignKey() = %v, want %v", got, want)
+	}
+
+	if _, err := ParseForeignKey("item.reward_id"); err == nil {
+		t.Errorf("ParseForeignKey() error = nil, want an error")
+	}
+}
+
+func TestCheckForeignKeys(t *testing.T) {
+	var separatedValue SeparatedValue
+	separatedValue.Init("csv", ".csv")
+	resolver := separatedValue.NewResolver("test/foreign_key/base", "test/foreign_key/versions")
+
+	resolved, err := resolver.ResolveE("", "max", []string{})
+	if err != nil {
+		t.Fatalf("ResolveE() error = %v", err)
+	}
+
+	foreignKey := ForeignKey{Table: "item", Column: "reward_id", ReferencedTable: "reward", ReferencedColumn: "id"}
+	err = CheckForeignKeys(resolved, []ForeignKey{foreignKey})
+	want := ValidationErrors{
+		&DanglingReferenceError{FilePath: "test/foreign_key/base/item.csv", Row: 3, Column: "reward_id", Id: 2, Value: "3", ForeignKey: foreignKey},
+		&DanglingReferenceError{FilePath: "test/foreign_key/versions/1_0_0_0/insert/item.csv", Version: "1_0_0_0", Row: 2, Column: "reward_id", Id: 3, Value: "9", ForeignKey: foreignKey},
+	}
+	if !reflect.DeepEqual(err, want) {
+		t.Errorf("CheckForeignKeys() error = %v, want %v", err, want)
+	}
+}
+
+func TestResolverCheckDeletesE(t *testing.T) {
+	var separatedValue SeparatedValue
+	separatedValue.Init("csv", ".csv")
+	resolver := separatedValue.NewResolver("test/delete_reference/base", "test/delete_reference/versions")
+
+	foreignKey := ForeignKey{Table: "item", Column: "reward_id", ReferencedTable: "reward", ReferencedColumn: "id"}
+	err := resolver.CheckDeletesE("", "max", []string{}, []ForeignKey{foreignKey})
+	want := ValidationErrors{
+		&DeletedReferenceError{
+			FilePath: "test/delete_reference/base/item.csv", Row: 3, Column: "reward_id", Id: 2, Value: "2",
+			DeleteFilePath: "test/delete_reference/versions/1_0_0_0/delete/reward.csv", Version: "1_0_0_0", ForeignKey: foreignKey,
+		},
+		&DeletedReferenceError{
+			FilePath: "test/delete_reference/versions/1_0_1_0/insert/item.csv", Row: 2, Column: "reward_id", Id: 3, Value: "2",
+			DeleteFilePath: "test/delete_reference/versions/1_0_0_0/delete/reward.csv", Version: "1_0_0_0", ForeignKey: foreignKey,
+		},
+	}
+	if !reflect.DeepEqual(err, want) {
+		t.Errorf("CheckDeletesE() error = %v, want %v", err, want)
+	}
+}
@@ -0,0 +1,352 @@
+package separated_value
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"math"
+	"os"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+type JSONFormat int
+
+const (
+	// JSONArray One JSON array of objects.
+	JSONArray JSONFormat = iota
+	// JSONLines One object per line.
+	JSONLines
+)
+
+func (separatedValue *SeparatedValue) NewJSONFile(path string, separatedValueMap map[Key]string, header []string, format JSONFormat, schema *Schema) {
+	err := separatedValue.NewJSONFileE(path, separatedValueMap, header, format, schema)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// NewJSONFileE Writes the map as JSON objects ordered by id, with the keys in the column order of ConvertRows.
+// When schema is not nil, the values of int, float and bool columns are written as JSON numbers and booleans,
+// and a blank cell of a nullable column as null. Otherwise every value is a string.
+func (separatedValue *SeparatedValue) NewJSONFileE(path string, separatedValueMap map[Key]string, header []string, format JSONFormat, schema *Schema) error {
+	return separatedValue.NewJSONFileFromRowsE(path, separatedValue.ConvertRows(separatedValueMap, header), format, schema)
+}
+
+// NewJSONFileFromRowsE Same as NewJSONFileE, but writes rows whose first row is the header, e.g. the result of LoadE.
+func (separatedValue *SeparatedValue) NewJSONFileFromRowsE(path string, rows [][]string, format JSONFormat, schema *Schema) (err error) {
+	jsonFile, err := os.Create(path)
+	if err != nil {
+		return &Error{FilePath: path, Err: errors.Wrap(err, "NewJSONFileCreateError")}
+	}
+	defer func(jsonFile *os.File) {
+		closeErr := jsonFile.Close()
+		if closeErr != nil && err == nil {
+			err = &Error{FilePath: path, Err: errors.Wrap(closeErr, "NewJSONFileCloseError")}
+		}
+	}(jsonFile)
+
+	writer := bufio.NewWriter(jsonFile)
+	if err := EncodeJSON(writer, rows, format, schema); err != nil {
+		var schemaError *SchemaError
+		var encodeError *Error
+		if errors.As(err, &schemaError) {
+			schemaError.FilePath = path
+		} else if errors.As(err, &encodeError) {
+			encodeError.FilePath = path
+		}
+		return err
+	}
+	if err := writer.Flush(); err != nil {
+		return &Error{FilePath: path, Err: errors.Wrap(err, "NewJSONFileWriteError")}
+	}
+
+	return nil
+}
+
+// EncodeJSON Writes rows whose first row is the header as JSON objects. See NewJSONFileE for the types of the values.
+// A value that does not match its column in the schema is a SchemaError, whose Row is the index of the row plus one.
+func EncodeJSON(writer io.Writer, rows [][]string, format JSONFormat, schema *Schema) error {
+	var objects [][]byte
+	if len(rows) != 0 {
+		header := rows[0]
+		for index, row := range rows[1:] {
+			object, err := encodeObject(header, row, schema)
+			if err != nil {
+				var schemaError *SchemaError
+				if errors.As(err, &schemaError) {
+					// The header is the first row, so the row of rows[1:][index] is index + 2.
+					schemaError.Row = index + 2
+				}
+				return err
+			}
+			objects = append(objects, object)
+		}
+	}
+
+	var buffer bytes.Buffer
+	switch format {
+	case JSONLines:
+		for _, object := range objects {
+			buffer.Write(object)
+			buffer.WriteByte('\n')
+		}
+	case JSONArray:
+		buffer.WriteByte('[')
+		for index, object := range objects {
+			if index != 0 {
+				buffer.WriteByte(',')
+			}
+			buffer.WriteString("\n  ")
+			buffer.Write(object)
+		}
+		if len(objects) != 0 {
+			buffer.WriteByte('\n')
+		}
+		buffer.WriteString("]\n")
+	default:
+		return &Error{Err: errors.Errorf("Unknown JSON format : %d", format)}
+	}
+
+	if _, err := writer.Write(buffer.Bytes()); err != nil {
+		return &Error{Err: errors.Wrap(err, "EncodeJSONWriteError")}
+	}
+
+	return nil
+}
+
+// encodeObject Encodes one row as an object whose keys are in the order of the header.
+func encodeObject(header []string, row []string, schema *Schema) ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteByte('{')
+	for columnNumber, name := range header {
+		value := ""
+		if columnNumber < len(row) {
+			value = row[columnNumber]
+		}
+
+		text, err := encodeJSONValue(name, value, schema)
+		if err != nil {
+			return nil, err
+		}
+
+		if columnNumber != 0 {
+			buffer.WriteByte(',')
+		}
+		buffer.WriteString(quoteJSON(name))
+		buffer.WriteByte(':')
+		buffer.WriteString(text)
+	}
+	buffer.WriteByte('}')
+
+	return buffer.Bytes(), nil
+}
+
+func encodeJSONValue(name string, value string, schema *Schema) (string, error) {
+	if schema == nil {
+		return quoteJSON(value), nil
+	}
+	column, ok := schema.column(name)
+	if !ok {
+		return quoteJSON(value), nil
+	}
+	if value == "" && column.Nullable {
+		return "null", nil
+	}
+	if reason := schema.check(name, value); reason != "" {
+		return "", &SchemaError{Column: name, Value: value, Reason: reason}
+	}
+
+	switch column.Type {
+	case IntColumn:
+		number, _ := strconv.Atoi(value)
+		return strconv.Itoa(number), nil
+	case FloatColumn:
+		number, _ := strconv.ParseFloat(value, 64)
+		if math.IsNaN(number) || math.IsInf(number, 0) {
+			return "", &SchemaError{Column: name, Value: value, Reason: "not a finite float"}
+		}
+		return strconv.FormatFloat(number, 'f', -1, 64), nil
+	case BoolColumn:
+		boolean, _ := strconv.ParseBool(value)
+		return strconv.FormatBool(boolean), nil
+	}
+
+	return quoteJSON(value), nil
+}
+
+// quoteJSON Returns the value as a JSON string, without escaping HTML characters.
+func quoteJSON(value string) string {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(value)
+
+	return string(bytes.TrimSuffix(buffer.Bytes(), []byte("\n")))
+}
+
+func (separatedValue *SeparatedValue) LoadJSON(filePath string) [][]string {
+	rows, err := separatedValue.LoadJSONE(filePath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return rows
+}
+
+// LoadJSONE The mirror of NewJSONFileE. Reads a JSON array of objects or JSON Lines into rows that NewFileE can write.
+func (separatedValue *SeparatedValue) LoadJSONE(filePath string) ([][]string, error) {
+	jsonFile, err := os.Open(filePath)
+	if err != nil {
+		return nil, &Error{FilePath: filePath, Err: errors.Wrap(err, "LoadJSONOpenError")}
+	}
+	defer jsonFile.Close()
+
+	rows, err := DecodeJSON(jsonFile)
+	if err != nil {
+		var decodeError *Error
+		if errors.As(err, &decodeError) {
+			decodeError.FilePath = filePath
+		}
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// DecodeJSON Reads a JSON array of objects or JSON Lines into rows, the first of which is the header.
+// The header is the keys in the order they first appear. A missing key and null are blank cells,
+// numbers are kept as written and booleans are "true" or "false". Nested objects and arrays are an error.
+func DecodeJSON(reader io.Reader) ([][]string, error) {
+	bufferedReader := bufio.NewReader(reader)
+	if bom, err := bufferedReader.Peek(3); err == nil && bytes.Equal(bom, []byte{0xEF, 0xBB, 0xBF}) {
+		_, _ = bufferedReader.Discard(3)
+	}
+	decoder := json.NewDecoder(bufferedReader)
+
+	isArray := false
+	if first, err := firstByte(bufferedReader); err == nil && first == '[' {
+		isArray = true
+		if _, err := decoder.Token(); err != nil {
+			return nil, &Error{Err: errors.Wrap(err, "DecodeJSONError")}
+		}
+	}
+
+	var header []string
+	columnNumbers := map[string]int{}
+	var objects []map[string]string
+	for decoder.More() {
+		// The header is the first row, so the row of objects[index] is index + 2.
+		row := len(objects) + 2
+		object, keys, err := decodeObject(decoder)
+		if err != nil {
+			return nil, &Error{Row: row, Err: err}
+		}
+		for _, key := range keys {
+			if _, ok := columnNumbers[key]; !ok {
+				columnNumbers[key] = len(header)
+				header = append(header, key)
+			}
+		}
+		objects = append(objects, object)
+	}
+
+	if isArray {
+		if _, err := decoder.Token(); err != nil {
+			return nil, &Error{Err: errors.Wrap(err, "DecodeJSONError")}
+		}
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, &Error{Err: errors.New("DecodeJSONError: unexpected data after the objects")}
+	}
+
+	rows := [][]string{header}
+	for _, object := range objects {
+		row := make([]string, len(header))
+		for key, value := range object {
+			row[columnNumbers[key]] = value
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// firstByte Returns the first byte that is not a white space, without consuming it.
+func firstByte(reader *bufio.Reader) (byte, error) {
+	for size := 1; ; size++ {
+		peeked, err := reader.Peek(size)
+		if len(peeked) < size {
+			return 0, err
+		}
+		switch peeked[size-1] {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		return peeked[size-1], nil
+	}
+}
+
+// decodeObject Reads one object, and returns its values and its keys in the order they are written.
+func decodeObject(decoder *json.Decoder) (map[string]string, []string, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "DecodeJSONError")
+	}
+	if delimiter, ok := token.(json.Delim); !ok || delimiter != '{' {
+		return nil, nil, errors.Errorf("DecodeJSONError: expected an object, not %v", token)
+	}
+
+	object := map[string]string{}
+	var keys []string
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "DecodeJSONError")
+		}
+		key := token.(string)
+
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return nil, nil, errors.Wrap(err, "DecodeJSONError")
+		}
+
+		value, err := decodeJSONValue(raw)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "DecodeJSONError: %s", key)
+		}
+		if _, ok := object[key]; !ok {
+			keys = append(keys, key)
+		}
+		object[key] = value
+	}
+
+	// The closing brace.
+	if _, err := decoder.Token(); err != nil {
+		return nil, nil, errors.Wrap(err, "DecodeJSONError")
+	}
+
+	return object, keys, nil
+}
+
+func decodeJSONValue(raw json.RawMessage) (string, error) {
+	raw = bytes.TrimSpace(raw)
+	switch {
+	case len(raw) == 0:
+		return "", nil
+	case raw[0] == '"':
+		var value string
+		err := json.Unmarshal(raw, &value)
+		return value, err
+	case raw[0] == '{' || raw[0] == '[':
+		return "", errors.New("nested objects and arrays are not supported")
+	case string(raw) == "null":
+		return "", nil
+	}
+
+	// Numbers and booleans are kept as written.
+	return string(raw), nil
+}
@@ -0,0 +1,147 @@
+package separated_value
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestEncodeJSON(t *testing.T) {
+	schema := &Schema{Columns: []Column{
+		{Name: "id", Type: IntColumn},
+		{Name: "rate", Type: FloatColumn},
+		{Name: "is_active", Type: BoolColumn},
+		{Name: "memo", Type: StringColumn, Nullable: true},
+	}}
+	rows := [][]string{
+		{"id", "name", "rate", "is_active", "memo"},
+		{"1", "<sword>", "0.50", "true", ""},
+		{"2", "shield", "1", "false", "note"},
+	}
+
+	tests := []struct {
+		name   string
+		format JSONFormat
+		schema *Schema
+		want   string
+	}{
+		{
+			name:   "array with schema",
+			format: JSONArray,
+			schema: schema,
+			want: "[\n" +
+				"  {\"id\":1,\"name\":\"<sword>\",\"rate\":0.5,\"is_active\":true,\"memo\":null},\n" +
+				"  {\"id\":2,\"name\":\"shield\",\"rate\":1,\"is_active\":false,\"memo\":\"note\"}\n" +
+				"]\n",
+		},
+		{
+			name:   "lines without schema",
+			format: JSONLines,
+			want: "{\"id\":\"1\",\"name\":\"<sword>\",\"rate\":\"0.50\",\"is_active\":\"true\",\"memo\":\"\"}\n" +
+				"{\"id\":\"2\",\"name\":\"shield\",\"rate\":\"1\",\"is_active\":\"false\",\"memo\":\"note\"}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buffer bytes.Buffer
+			if err := EncodeJSON(&buffer, rows, tt.format, tt.schema); err != nil {
+				t.Fatal(err)
+			}
+			if got := buffer.String(); got != tt.want {
+				t.Errorf("EncodeJSON() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("schema error", func(t *testing.T) {
+		invalidRows := [][]string{{"id", "rate"}, {"1", "0.5"}, {"2", "high"}}
+		err := EncodeJSON(&bytes.Buffer{}, invalidRows, JSONArray, schema)
+		var schemaError *SchemaError
+		if !errors.As(err, &schemaError) || schemaError.Row != 3 || schemaError.Column != "rate" {
+			t.Errorf("EncodeJSON() error = %v", err)
+		}
+	})
+}
+
+func TestDecodeJSON(t *testing.T) {
+	want := [][]string{
+		{"id", "name", "rate", "memo"},
+		{"1", "sword", "0.5", ""},
+		{"2", "shield", "", "note"},
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		want    [][]string
+		wantErr bool
+	}{
+		{
+			name:    "array",
+			content: "\xEF\xBB\xBF [{\"id\":1,\"name\":\"sword\",\"rate\":0.5,\"memo\":null},\n{\"id\":2,\"name\":\"shield\",\"memo\":\"note\"}]\n",
+			want:    want,
+		},
+		{
+			name:    "lines",
+			content: "{\"id\":1,\"name\":\"sword\",\"rate\":0.5}\n{\"id\":2,\"name\":\"shield\",\"memo\":\"note\"}\n",
+			want:    want,
+		},
+		{
+			name:    "nested",
+			content: "{\"id\":1,\"tags\":[\"a\"]}\n",
+			wantErr: true,
+		},
+		{
+			name:    "not an object",
+			content: "[1, 2]",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeJSON(strings.NewReader(tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecodeJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DecodeJSON() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewJSONFileE(t *testing.T) {
+	var separatedValue SeparatedValue
+	separatedValue.Init("csv", ".csv")
+
+	separatedValueMap := map[Key]string{
+		{Id: 2, Key: "id"}: "2", {Id: 2, Key: "name"}: "shield",
+		{Id: 1, Key: "id"}: "1", {Id: 1, Key: "name"}: "sword",
+	}
+	path := filepath.Join(t.TempDir(), "item.jsonl")
+	if err := separatedValue.NewJSONFileE(path, separatedValueMap, []string{"id", "name"}, JSONLines, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "{\"id\":\"1\",\"name\":\"sword\"}\n{\"id\":\"2\",\"name\":\"shield\"}\n"
+	if string(content) != want {
+		t.Errorf("NewJSONFileE() = %v, want %v", string(content), want)
+	}
+
+	rows, err := separatedValue.LoadJSONE(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rows, separatedValue.ConvertRows(separatedValueMap, []string{"id", "name"})) {
+		t.Errorf("LoadJSONE() = %v", rows)
+	}
+}
@@ -0,0 +1,362 @@
+package separated_value
+
+import (
+	"bufio"
+	"encoding/csv"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// LoadOptions The markers of the rows and columns that the loaders exclude.
+//
+// A row is a comment when its line starts with one of CommentPrefixes ("#" when empty).
+// A column is excluded when its header is "#", starts with one of ExclusionPrefixes, or matches ExclusionPattern.
+// When IsReportExcludedColumns is true, the excluded columns of every file are logged.
+type LoadOptions struct {
+	CommentPrefixes         []string
+	ExclusionPrefixes       []string
+	ExclusionPattern        *regexp.Regexp
+	IsReportExcludedColumns bool
+}
+
+// SetLoadOptions Changes the markers used by Load, LoadMap, Each and the other loaders.
+func (separatedValue *SeparatedValue) SetLoadOptions(options LoadOptions) error {
+	changed := *separatedValue
+	changed.loadOptions = options
+	if err := changed.validate(); err != nil {
+		return err
+	}
+
+	separatedValue.loadOptions = options
+
+	return nil
+}
+
+// QuotePolicy Which fields are quoted when writing. A field is always read with or without quotes.
+type QuotePolicy int
+
+const (
+	// QuoteMinimal Only the fields that contain the delimiter, a quote or a line break, or start with a space.
+	QuoteMinimal QuotePolicy = iota
+	// QuoteAll Every field.
+	QuoteAll
+)
+
+// LineEnding The line ending of the written files. Both are accepted when reading.
+type LineEnding string
+
+const (
+	LF   LineEnding = "\n"
+	CRLF LineEnding = "\r\n"
+)
+
+// Option A setting of New.
+type Option func(separatedValue *SeparatedValue)
+
+// New Makes a SeparatedValue with the options. Without options, it reads and writes csv files with the extension ".csv",
+// reads and writes UTF-8, strips and writes a BOM, writes LF
+// and requires every row to have as many fields as the first one.
+// The options are validated here, so that a wrong setting is not found only when a file is read.
+func New(options ...Option) (*SeparatedValue, error) {
+	separatedValue := &SeparatedValue{delimiter: ','}
+	for _, option := range options {
+		option(separatedValue)
+	}
+
+	if separatedValue.extension == "" {
+		separatedValue.extension = ".csv"
+		if separatedValue.delimiter == '\t' {
+			separatedValue.extension = ".tsv"
+		}
+	}
+
+	if err := separatedValue.validate(); err != nil {
+		return nil, err
+	}
+
+	return separatedValue, nil
+}
+
+// WithDelimiter e.g. '\t', '|' or ';'.
+func WithDelimiter(delimiter rune) Option {
+	return func(separatedValue *SeparatedValue) {
+		separatedValue.delimiter = delimiter
+	}
+}
+
+// WithExtension The extension of the files found by GetFilePathRecursive. The default is ".tsv" for '\t', otherwise ".csv".
+func WithExtension(extension string) Option {
+	return func(separatedValue *SeparatedValue) {
+		separatedValue.extension = extension
+	}
+}
+
+func WithQuote(quotePolicy QuotePolicy) Option {
+	return func(separatedValue *SeparatedValue) {
+		separatedValue.quotePolicy = quotePolicy
+	}
+}
+
+// WithLazyQuotes Allows a quote in an unquoted field and a non-doubled quote in a quoted field, as in encoding/csv.
+func WithLazyQuotes(isLazyQuotes bool) Option {
+	return func(separatedValue *SeparatedValue) {
+		separatedValue.isLazyQuotes = isLazyQuotes
+	}
+}
+
+// WithTrimLeadingSpace Ignores the leading white space of the fields when reading.
+func WithTrimLeadingSpace(isTrimLeadingSpace bool) Option {
+	return func(separatedValue *SeparatedValue) {
+		separatedValue.isTrimLeadingSpace = isTrimLeadingSpace
+	}
+}
+
+// WithBOMStripping Whether the BOM at the start of a file is removed when reading. The default is true.
+func WithBOMStripping(isStrip bool) Option {
+	return func(separatedValue *SeparatedValue) {
+		separatedValue.isKeepBOM = !isStrip
+	}
+}
+
+// WithBOMWriting Whether a BOM is written at the start of a file. The default is true, so that Excel detects UTF-8.
+func WithBOMWriting(isWrite bool) Option {
+	return func(separatedValue *SeparatedValue) {
+		separatedValue.isOmitBOM = !isWrite
+	}
+}
+
+func WithLineEnding(lineEnding LineEnding) Option {
+	return func(separatedValue *SeparatedValue) {
+		separatedValue.lineEnding = lineEnding
+	}
+}
+
+// WithFieldsPerRecord The number of fields of every row, as in encoding/csv: 0 requires the number of the first row
+// (the default), a positive number requires that number and -1 allows any number.
+// The loaders read a missing cell of a short row as blank, and ignore the cells beyond the header.
+func WithFieldsPerRecord(fieldsPerRecord int) Option {
+	return func(separatedValue *SeparatedValue) {
+		separatedValue.fieldsPerRecord = fieldsPerRecord
+	}
+}
+
+func WithLoadOptions(options LoadOptions) Option {
+	return func(separatedValue *SeparatedValue) {
+		separatedValue.loadOptions = options
+	}
+}
+
+func (separatedValue *SeparatedValue) validate() error {
+	delimiter := separatedValue.comma()
+	if delimiter == '"' || delimiter == '\r' || delimiter == '\n' || !utf8.ValidRune(delimiter) || delimiter == utf8.RuneError {
+		return &Error{Err: errors.Errorf("Invalid delimiter : %q", delimiter)}
+	}
+	if delimiter == '#' && separatedValue.loadOptions.isDefaultComment() {
+		return &Error{Err: errors.New("The delimiter must not be the comment character #")}
+	}
+	if !strings.HasPrefix(separatedValue.extension, ".") || len(separatedValue.extension) < 2 {
+		return &Error{Err: errors.Errorf("Invalid extension : %q", separatedValue.extension)}
+	}
+	if separatedValue.quotePolicy != QuoteMinimal && separatedValue.quotePolicy != QuoteAll {
+		return &Error{Err: errors.Errorf("Unknown quote policy : %d", separatedValue.quotePolicy)}
+	}
+	if separatedValue.lineEnding != "" && separatedValue.lineEnding != LF && separatedValue.lineEnding != CRLF {
+		return &Error{Err: errors.Errorf("Unknown line ending : %q", separatedValue.lineEnding)}
+	}
+	if !separatedValue.readEncoding.isValid() {
+		return &Error{Err: errors.Errorf("Unknown encoding : %q", separatedValue.readEncoding)}
+	}
+	if !separatedValue.writeEncoding.isValid() {
+		return &Error{Err: errors.Errorf("Unknown encoding : %q", separatedValue.writeEncoding)}
+	}
+	if separatedValue.writeEncoding == AutoDetect {
+		return &Error{Err: errors.New("The encoding to write cannot be detected")}
+	}
+	if separatedValue.fieldsPerRecord < -1 {
+		return &Error{Err: errors.Errorf("Invalid number of fields per record : %d", separatedValue.fieldsPerRecord)}
+	}
+
+	for _, prefix := range separatedValue.loadOptions.CommentPrefixes {
+		if prefix == "" {
+			return &Error{Err: errors.New("A comment prefix must not be empty")}
+		}
+	}
+	for _, prefix := range separatedValue.loadOptions.ExclusionPrefixes {
+		if prefix == "" {
+			return &Error{Err: errors.New("An exclusion prefix must not be empty")}
+		}
+	}
+
+	return nil
+}
+
+// WriteSettings Returns a text of every setting that changes the written files: the extension, the delimiter,
+// the quote policy, the line ending, the encoding and the BOM. The defaults are spelled out, so that the same files give the same text.
+func (separatedValue *SeparatedValue) WriteSettings() string {
+	lineEnding := separatedValue.lineEnding
+	if lineEnding == "" {
+		lineEnding = LF
+	}
+	encoding := separatedValue.writeEncoding
+	if encoding == "" {
+		encoding = UTF8
+	}
+
+	return strings.Join([]string{
+		"extension=" + strconv.Quote(separatedValue.extension),
+		"delimiter=" + strconv.QuoteRune(separatedValue.comma()),
+		"quote=" + strconv.Itoa(int(separatedValue.quotePolicy)),
+		"line_ending=" + strconv.Quote(string(lineEnding)),
+		"encoding=" + string(encoding),
+		"bom=" + strconv.FormatBool(!separatedValue.isOmitBOM),
+	}, " ")
+}
+
+// comma The delimiter, which is a comma for the zero value.
+func (separatedValue *SeparatedValue) comma() rune {
+	if separatedValue.delimiter == 0 {
+		return ','
+	}
+
+	return separatedValue.delimiter
+}
+
+// writeRows Writes the rows with the delimiter, the quote policy and the line ending.
+func (separatedValue *SeparatedValue) writeRows(writer io.Writer, rows [][]string) error {
+	isCRLF := separatedValue.lineEnding == CRLF
+
+	if separatedValue.quotePolicy != QuoteAll {
+		csvWriter := csv.NewWriter(writer)
+		csvWriter.Comma = separatedValue.comma()
+		csvWriter.UseCRLF = isCRLF
+
+		// WriteAll flushes the writer, so the error of the flush is also returned here.
+		return csvWriter.WriteAll(rows)
+	}
+
+	bufferedWriter := bufio.NewWriter(writer)
+	for _, row := range rows {
+		for index, field := range row {
+			if index != 0 {
+				bufferedWriter.WriteRune(separatedValue.comma())
+			}
+			field = strings.ReplaceAll(field, `"`, `""`)
+			// Same as encoding/csv, a line break in a field follows the line ending.
+			if isCRLF {
+				field = strings.ReplaceAll(strings.ReplaceAll(field, "\r\n", "\n"), "\n", "\r\n")
+			}
+			bufferedWriter.WriteString(`"` + field + `"`)
+		}
+		if isCRLF {
+			bufferedWriter.WriteString("\r\n")
+		} else {
+			bufferedWriter.WriteByte('\n')
+		}
+	}
+
+	return bufferedWriter.Flush()
+}
+
+// isExcludedColumn Returns true if the column of the header is excluded.
+func (options LoadOptions) isExcludedColumn(name string) bool {
+	if name == "#" {
+		return true
+	}
+	for _, prefix := range options.ExclusionPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+
+	return options.ExclusionPattern != nil && options.ExclusionPattern.MatchString(name)
+}
+
+// isDefaultComment Returns true if the comment is the single "#" that encoding/csv handles by itself.
+func (options LoadOptions) isDefaultComment() bool {
+	return len(options.CommentPrefixes) == 0 || (len(options.CommentPrefixes) == 1 && options.CommentPrefixes[0] == "#")
+}
+
+// commentReader Blanks the lines starting with one of the prefixes, so that encoding/csv skips them as empty lines
+// and the line numbers of the other rows do not change. A line inside a quoted field is not a comment.
+type commentReader struct {
+	reader   *bufio.Reader
+	prefixes []string
+	comma    byte
+	pending  []byte
+	isQuoted bool
+	err      error
+}
+
+func newCommentReader(reader *bufio.Reader, prefixes []string, comma rune) *commentReader {
+	return &commentReader{reader: reader, prefixes: prefixes, comma: byte(comma)}
+}
+
+func (commentReader *commentReader) Read(buffer []byte) (int, error) {
+	for len(commentReader.pending) == 0 {
+		if commentReader.err != nil {
+			return 0, commentReader.err
+		}
+
+		line, err := commentReader.reader.ReadBytes('\n')
+		commentReader.err = err
+		if len(line) == 0 {
+			continue
+		}
+
+		if !commentReader.isQuoted && commentReader.isComment(line) {
+			line = line[:0]
+			if commentReader.err == nil {
+				line = append(line, '\n')
+			}
+		} else {
+			commentReader.scan(line)
+		}
+		commentReader.pending = line
+	}
+
+	size := copy(buffer, commentReader.pending)
+	commentReader.pending = commentReader.pending[size:]
+
+	return size, nil
+}
+
+func (commentReader *commentReader) isComment(line []byte) bool {
+	for _, prefix := range commentReader.prefixes {
+		if strings.HasPrefix(string(line), prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// scan Follows whether the end of the line is inside a quoted field. A quote opens a field only at its start,
+// and a doubled quote inside a quoted field is an escaped quote.
+func (commentReader *commentReader) scan(line []byte) {
+	isFieldStart := true
+	for index := 0; index < len(line); index++ {
+		character := line[index]
+		switch {
+		case commentReader.isQuoted:
+			if character == '"' {
+				if index+1 < len(line) && line[index+1] == '"' {
+					index++
+				} else {
+					commentReader.isQuoted = false
+				}
+			}
+			isFieldStart = false
+		case character == commentReader.comma:
+			isFieldStart = true
+		case character == '"' && isFieldStart:
+			commentReader.isQuoted = true
+		default:
+			isFieldStart = false
+		}
+	}
+}
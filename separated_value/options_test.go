@@ -0,0 +1,217 @@
+package separated_value
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestSetLoadOptions(t *testing.T) {
+	tests := []struct {
+		name      string
+		options   LoadOptions
+		want      [][]string
+		wantLines []int
+		wantErr   bool
+	}{
+		{
+			// "//" is not a comment, so the row has a different number of fields.
+			name:    "default",
+			options: LoadOptions{},
+			wantErr: true,
+		},
+		{
+			name: "prefixes",
+			options: LoadOptions{
+				CommentPrefixes:   []string{"//", ";"},
+				ExclusionPrefixes: []string{"#"},
+				ExclusionPattern:  regexp.MustCompile("^_"),
+			},
+			want: [][]string{
+				{"id", "name", "price"},
+				{"1", "sword", "100"},
+				{"2", "multi\n// line", "200"},
+			},
+			wantLines: []int{1, 3, 5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var separatedValue SeparatedValue
+			separatedValue.Init("csv", ".csv")
+			if err := separatedValue.SetLoadOptions(tt.options); err != nil {
+				t.Fatal(err)
+			}
+
+			got, lines, err := separatedValue.load("./test/options.csv", true, true)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (!reflect.DeepEqual(got, tt.want) || !reflect.DeepEqual(lines, tt.wantLines)) {
+				t.Errorf("load() = %q %v, want %q %v", got, lines, tt.want, tt.wantLines)
+			}
+		})
+	}
+
+	t.Run("empty prefix", func(t *testing.T) {
+		var separatedValue SeparatedValue
+		if err := separatedValue.SetLoadOptions(LoadOptions{CommentPrefixes: []string{""}}); err == nil {
+			t.Error("SetLoadOptions() error = nil")
+		}
+	})
+}
+
+func TestReaderExcludedColumns(t *testing.T) {
+	var separatedValue SeparatedValue
+	separatedValue.Init("csv", ".csv")
+	if err := separatedValue.SetLoadOptions(LoadOptions{CommentPrefixes: []string{"//", ";"}, ExclusionPrefixes: []string{"#", "_"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := separatedValue.Open("./test/options.csv", true, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+
+	if !reader.Next() {
+		t.Fatal(reader.Err())
+	}
+	if want := []string{"#memo", "_note", "#"}; !reflect.DeepEqual(reader.ExcludedColumns(), want) {
+		t.Errorf("ExcludedColumns() = %v, want %v", reader.ExcludedColumns(), want)
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name          string
+		options       []Option
+		wantExtension string
+		wantErr       bool
+	}{
+		{name: "default", options: nil, wantExtension: ".csv"},
+		{name: "tab", options: []Option{WithDelimiter('\t')}, wantExtension: ".tsv"},
+		{name: "extension", options: []Option{WithDelimiter(';'), WithExtension(".txt")}, wantExtension: ".txt"},
+		{name: "quote delimiter", options: []Option{WithDelimiter('"')}, wantErr: true},
+		{name: "line break delimiter", options: []Option{WithDelimiter('\n')}, wantErr: true},
+		{name: "comment delimiter", options: []Option{WithDelimiter('#')}, wantErr: true},
+		{name: "comment delimiter with other comment", options: []Option{WithDelimiter('#'), WithLoadOptions(LoadOptions{CommentPrefixes: []string{"//"}})}, wantExtension: ".csv"},
+		{name: "extension without dot", options: []Option{WithExtension("csv")}, wantErr: true},
+		{name: "unknown quote policy", options: []Option{WithQuote(QuotePolicy(9))}, wantErr: true},
+		{name: "unknown line ending", options: []Option{WithLineEnding("\r")}, wantErr: true},
+		{name: "fields per record", options: []Option{WithFieldsPerRecord(-2)}, wantErr: true},
+		{name: "empty exclusion prefix", options: []Option{WithLoadOptions(LoadOptions{ExclusionPrefixes: []string{""}})}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New(tt.options...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got.GetExtension() != tt.wantExtension {
+				t.Errorf("GetExtension() = %v, want %v", got.GetExtension(), tt.wantExtension)
+			}
+		})
+	}
+}
+
+func TestNewLoadAndNewFile(t *testing.T) {
+	separatedValue, err := New(WithDelimiter('|'), WithExtension(".txt"), WithTrimLeadingSpace(true),
+		WithQuote(QuoteAll), WithLineEnding(CRLF), WithBOMWriting(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := separatedValue.LoadE("./test/pipe.txt", true, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{{"id", "name", "memo"}, {"1", "sword", "a|b"}, {"2", "shield", "c"}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("LoadE() = %v, want %v", rows, want)
+	}
+
+	path := filepath.Join(t.TempDir(), "item.txt")
+	if err := separatedValue.NewFileE(path, [][]string{{"id", "memo"}, {"1", "say \"hi\"\nbye"}}); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "\"id\"|\"memo\"\r\n\"1\"|\"say \"\"hi\"\"\r\nbye\"\r\n"; string(content) != want {
+		t.Errorf("NewFileE() = %q, want %q", content, want)
+	}
+
+	strict, err := New(WithFieldsPerRecord(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := strict.LoadE("./test/sample.csv", true, true); err == nil {
+		t.Error("LoadE() error = nil, want a field count error")
+	}
+}
+
+func TestLoadShortRows(t *testing.T) {
+	separatedValue, err := New(WithFieldsPerRecord(-1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	directoryPath := t.TempDir()
+	write := func(content string) string {
+		path := filepath.Join(directoryPath, "item.csv")
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	path := write("name,id\nfoo\n")
+	var invalidIDError *InvalidIDError
+	if _, err := separatedValue.LoadMapE(path, nil, true); !errors.As(err, &invalidIDError) || invalidIDError.Row != 2 {
+		t.Errorf("LoadMapE() error = %v, want an InvalidIDError on row 2", err)
+	}
+	if _, err := separatedValue.LoadRecordMapE(path, KeyDefinition{Type: StringKey}, nil, true); !errors.As(err, &invalidIDError) || invalidIDError.Row != 2 {
+		t.Errorf("LoadRecordMapE() error = %v, want an InvalidIDError on row 2", err)
+	}
+
+	path = write("id,name,level\n1,sword\n")
+	var blankCellError *BlankCellError
+	if _, err := separatedValue.LoadMapE(path, nil, true); !errors.As(err, &blankCellError) || blankCellError.Row != 2 || blankCellError.Column != "level" {
+		t.Errorf("LoadMapE() error = %v, want a BlankCellError of level on row 2", err)
+	}
+
+	items, err := LoadIntoE[decodeItem](separatedValue, path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []decodeItem{{Id: 1, Name: "sword"}}; !reflect.DeepEqual(items, want) {
+		t.Errorf("LoadIntoE() = %+v, want %+v", items, want)
+	}
+}
+
+func TestWriteSettings(t *testing.T) {
+	var initialized SeparatedValue
+	initialized.Init("csv", ".csv")
+	defaults, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if initialized.WriteSettings() != defaults.WriteSettings() {
+		t.Errorf("WriteSettings() = %v, want %v", initialized.WriteSettings(), defaults.WriteSettings())
+	}
+
+	for _, option := range []Option{WithDelimiter(';'), WithQuote(QuoteAll), WithLineEnding(CRLF), WithWriteEncoding(ShiftJIS), WithBOMWriting(false)} {
+		changed, err := New(option)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if changed.WriteSettings() == defaults.WriteSettings() {
+			t.Errorf("WriteSettings() = %v, want a change", changed.WriteSettings())
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package separated_value
+
+import (
+	"strconv"
+)
+
+// Origin Where a cell value came from.
+// Operation is "base" for the value read from the base file, otherwise "insert" or "update".
+// Version is the name of the version directory, and is empty for the base file.
+type Origin struct {
+	FilePath  string
+	Version   string
+	Operation string
+	Row       int
+}
+
+// Provenance The origin of every cell of a map[Key]string.
+type Provenance map[Key]Origin
+
+// LoadMapWithProvenanceE Same as LoadMapE, and also returns the origin of every cell.
+func (separatedValue *SeparatedValue) LoadMapWithProvenanceE(filePath string, filterNames []string, isColumnExclusion bool) (map[Key]string, Provenance, error) {
+	result, rowNumbers, err := separatedValue.loadRecordMap(filePath, KeyDefinition{}, filterNames, isColumnExclusion, nil, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	provenance := make(Provenance)
+	provenance.record(Origin{FilePath: filePath, Operation: "base"}, []string{}, result, rowNumbers)
+
+	return keyMap(result), provenance, nil
+}
+
+// LoadByDirectoryPathWithProvenanceE Same as LoadByDirectoryPathE, and also records in provenance
+// the origin of every cell inserted or updated, and forgets the deleted ones.
+func (separatedValue *SeparatedValue) LoadByDirectoryPathWithProvenanceE(directoryPath string, fileName string, baseMap map[Key]string, provenance Provenance, filterNames []string) (map[Key]string, error) {
+	return separatedValue.loadByDirectoryPath(directoryPath, fileName, baseMap, filterNames, nil, provenance, nil)
+}
+
+// record Updates the origins of the cells changed by the operation.
+// baseIds are the IDs that existed before the operation, so that the records skipped by validation are not recorded.
+// The records are those of the integer "id" column.
+func (provenance Provenance) record(origin Origin, baseIds []string, editMap map[RecordKey]string, rowNumbers map[string]int) {
+	isBaseId := idSet(baseIds)
+	for recordKey := range editMap {
+		isExist := isBaseId[recordKey.Id]
+		id, _ := strconv.Atoi(recordKey.Id)
+		mapKey := Key{Id: id, Key: recordKey.Key}
+
+		switch origin.Operation {
+		case "delete":
+			if isExist {
+				delete(provenance, mapKey)
+			}
+		case "update":
+			if isExist {
+				provenance[mapKey] = Origin{FilePath: origin.FilePath, Version: origin.Version, Operation: origin.Operation, Row: rowNumbers[recordKey.Id]}
+			}
+		default:
+			if !isExist {
+				provenance[mapKey] = Origin{FilePath: origin.FilePath, Version: origin.Version, Operation: origin.Operation, Row: rowNumbers[recordKey.Id]}
+			}
+		}
+	}
+}
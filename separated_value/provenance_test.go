@@ -0,0 +1,36 @@
+package separated_value
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResolverProvenance(t *testing.T) {
+	var separatedValue SeparatedValue
+	separatedValue.Init("csv", ".csv")
+	resolver := separatedValue.NewResolver("test/resolver/base", "test/resolver/versions")
+
+	got, err := resolver.ResolveE("", "max", []string{})
+	if err != nil {
+		t.Fatalf("ResolveE() error = %v", err)
+	}
+
+	base := Origin{FilePath: "test/resolver/base/item.csv", Operation: "base", Row: 2}
+	insert := Origin{FilePath: "test/resolver/versions/1_0_1_0/insert/item.csv", Version: "1_0_1_0", Operation: "insert", Row: 2}
+	update := Origin{FilePath: "test/resolver/versions/1_0_10_0/update/item.csv", Version: "1_0_10_0", Operation: "update", Row: 2}
+	want := Provenance{
+		{Id: 1, Key: "id"}: update, {Id: 1, Key: "name"}: update, {Id: 1, Key: "price"}: update,
+		{Id: 3, Key: "id"}: insert, {Id: 3, Key: "name"}: insert, {Id: 3, Key: "price"}: insert,
+	}
+	if !reflect.DeepEqual(got["item.csv"].Provenance, want) {
+		t.Errorf("Provenance = %v, want %v", got["item.csv"].Provenance, want)
+	}
+
+	_, provenance, err := separatedValue.LoadMapWithProvenanceE("test/resolver/base/item.csv", []string{}, true)
+	if err != nil {
+		t.Fatalf("LoadMapWithProvenanceE() error = %v", err)
+	}
+	if provenance[Key{Id: 1, Key: "price"}] != base {
+		t.Errorf("LoadMapWithProvenanceE() = %v, want %v", provenance[Key{Id: 1, Key: "price"}], base)
+	}
+}
@@ -0,0 +1,185 @@
+package separated_value
+
+import (
+	"bufio"
+	"encoding/csv"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/stepupdream/golang-support-tool/array"
+)
+
+// Reader Reads a separated value file row by row with constant memory.
+// BOM stripping, row comments and column exclusion (see LoadOptions) are applied on the fly, in the same way as Load.
+type Reader struct {
+	file                 *os.File
+	reader               *csv.Reader
+	filePath             string
+	isColumnExclusion    bool
+	isHeaderRead         bool
+	disableColumnIndexes []int
+	excludedColumns      []string
+	loadOptions          LoadOptions
+	row                  []string
+	line                 int
+	err                  error
+}
+
+// Open Opens the file for reading row by row. The caller must Close the Reader.
+func (separatedValue *SeparatedValue) Open(filePath string, isRowExclusion bool, isColumnExclusion bool) (*Reader, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, &Error{FilePath: filePath, Err: errors.Wrap(err, "LoadSeparatedValueOpenError")}
+	}
+
+	reader, err := separatedValue.decode(bufio.NewReader(file))
+	if err != nil {
+		_ = file.Close()
+		return nil, &Error{FilePath: filePath, Err: err}
+	}
+
+	// If BOM is included, delete the BOM
+	// https://pinzolo.github.io/2017/03/29/utf8-csv-with-bom-on-golang.html
+	bytes, err := reader.Peek(3)
+	if err != nil {
+		_ = file.Close()
+		return nil, &Error{FilePath: filePath, Err: errors.Wrap(err, "LoadSeparatedValueNewReaderError")}
+	} else if !separatedValue.isKeepBOM && bytes[0] == 0xEF && bytes[1] == 0xBB && bytes[2] == 0xBF {
+		_, err := reader.Discard(3)
+		if err != nil {
+			_ = file.Close()
+			return nil, &Error{FilePath: filePath, Err: errors.Wrap(err, "SeparatedValueDiscardError")}
+		}
+	}
+
+	comma := separatedValue.comma()
+	options := separatedValue.loadOptions
+	var source io.Reader = reader
+	if isRowExclusion && !options.isDefaultComment() {
+		source = newCommentReader(reader, options.CommentPrefixes, comma)
+	}
+
+	separatedValueReader := csv.NewReader(source)
+	separatedValueReader.ReuseRecord = true
+	separatedValueReader.Comma = comma
+	separatedValueReader.LazyQuotes = separatedValue.isLazyQuotes
+	separatedValueReader.TrimLeadingSpace = separatedValue.isTrimLeadingSpace
+	separatedValueReader.FieldsPerRecord = separatedValue.fieldsPerRecord
+	if isRowExclusion && options.isDefaultComment() {
+		separatedValueReader.Comment = '#'
+	}
+
+	return &Reader{
+		file:              file,
+		reader:            separatedValueReader,
+		filePath:          filePath,
+		isColumnExclusion: isColumnExclusion,
+		loadOptions:       options,
+	}, nil
+}
+
+// Each Calls fn for every row of the file. Reading stops when fn returns an error, and the error is returned.
+// The row passed to fn is only valid until fn returns.
+func (separatedValue *SeparatedValue) Each(filePath string, isRowExclusion bool, isColumnExclusion bool, fn func(row []string, line int) error) (err error) {
+	reader, err := separatedValue.Open(filePath, isRowExclusion, isColumnExclusion)
+	if err != nil {
+		return err
+	}
+	defer func(reader *Reader) {
+		closeErr := reader.Close()
+		if closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}(reader)
+
+	for reader.Next() {
+		if err := fn(reader.Row(), reader.Line()); err != nil {
+			return err
+		}
+	}
+
+	return reader.Err()
+}
+
+// Next Advances to the next row. It returns false at the end of the file or on an error, which Err reports.
+func (reader *Reader) Next() bool {
+	if reader.err != nil {
+		return false
+	}
+
+	row, err := reader.reader.Read()
+	if err == io.EOF {
+		return false
+	}
+	if err != nil {
+		var parseError *csv.ParseError
+		if errors.As(err, &parseError) {
+			reader.err = &Error{FilePath: reader.filePath, Row: parseError.Line, Err: err}
+		} else {
+			reader.err = &Error{FilePath: reader.filePath, Err: errors.Wrap(err, "SeparatedValueReadError")}
+		}
+		return false
+	}
+
+	reader.line, _ = reader.reader.FieldPos(0)
+
+	// The first row is the header, and decides the columns to exclude.
+	if !reader.isHeaderRead {
+		reader.isHeaderRead = true
+		if reader.isColumnExclusion {
+			for index, value := range row {
+				if reader.loadOptions.isExcludedColumn(value) {
+					reader.disableColumnIndexes = append(reader.disableColumnIndexes, index)
+					reader.excludedColumns = append(reader.excludedColumns, value)
+				}
+			}
+			if reader.loadOptions.IsReportExcludedColumns && len(reader.excludedColumns) != 0 {
+				log.Println("Excluded columns : " + strings.Join(reader.excludedColumns, ", ") + position(reader.filePath, reader.line, ""))
+			}
+		}
+	}
+
+	if len(reader.disableColumnIndexes) == 0 {
+		reader.row = row
+		return true
+	}
+
+	reader.row = reader.row[:0]
+	for index, value := range row {
+		if !array.IntContains(reader.disableColumnIndexes, index) {
+			reader.row = append(reader.row, value)
+		}
+	}
+
+	return true
+}
+
+// Row Returns the current row. The slice is reused, so it is only valid until the next call to Next.
+func (reader *Reader) Row() []string {
+	return reader.row
+}
+
+// ExcludedColumns Returns the headers of the columns excluded from the rows, once the header has been read.
+func (reader *Reader) ExcludedColumns() []string {
+	return reader.excludedColumns
+}
+
+// Line Returns the line number in the file of the current row.
+func (reader *Reader) Line() int {
+	return reader.line
+}
+
+func (reader *Reader) Err() error {
+	return reader.err
+}
+
+func (reader *Reader) Close() error {
+	if err := reader.file.Close(); err != nil {
+		return &Error{FilePath: reader.filePath, Err: errors.Wrap(err, "LoadSeparatedValueCloseError")}
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package separated_value
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReader(t *testing.T) {
+	var separatedValue SeparatedValue
+	separatedValue.Init("csv", ".csv")
+
+	reader, err := separatedValue.Open("test/sample2.csv", true, true)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer reader.Close()
+
+	var rows [][]string
+	var lines []int
+	for reader.Next() {
+		rows = append(rows, append([]string(nil), reader.Row()...))
+		lines = append(lines, reader.Line())
+	}
+	if err := reader.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+
+	wantRows := [][]string{
+		{"id", "sample", "level"},
+		{"2", "bbb", "43"},
+	}
+	wantLines := []int{1, 3}
+	if !reflect.DeepEqual(rows, wantRows) {
+		t.Errorf("Row() = %v, want %v", rows, wantRows)
+	}
+	if !reflect.DeepEqual(lines, wantLines) {
+		t.Errorf("Line() = %v, want %v", lines, wantLines)
+	}
+}
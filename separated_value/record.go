@@ -0,0 +1,497 @@
+package separated_value
+
+import (
+	"log"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/stepupdream/golang-support-tool/array"
+	"github.com/stepupdream/golang-support-tool/directory"
+	supportFile "github.com/stepupdream/golang-support-tool/file"
+)
+
+// RecordIdSeparator Separates the values of the key columns in RecordKey.Id.
+const RecordIdSeparator = "\x1f"
+
+type KeyType int
+
+const (
+	IntKey KeyType = iota
+	StringKey
+)
+
+// KeyDefinition The columns that identify a record, and the type of their values.
+// The zero value means the "id" column as an integer, which is the same as LoadMap.
+//
+// LoadMap, LoadByDirectoryPath and ValidateMap are this with the zero value. Diff, Resolver, the foreign key checks,
+// provenance and the SQL exporter work only on map[Key]string, that is on the integer "id" column.
+type KeyDefinition struct {
+	Columns []string
+	Type    KeyType
+}
+
+// RecordKey Generalized Key for tables keyed by string codes or by several columns.
+// Id is the values of the key columns joined with RecordIdSeparator. Use KeyDefinition.Id to make it.
+type RecordKey struct {
+	Id  string
+	Key string
+}
+
+func (definition KeyDefinition) columns() []string {
+	if len(definition.Columns) == 0 {
+		return []string{"id"}
+	}
+
+	return definition.Columns
+}
+
+// Id Makes RecordKey.Id from the values of the key columns, in the order of Columns.
+func (definition KeyDefinition) Id(values ...string) string {
+	return strings.Join(values, RecordIdSeparator)
+}
+
+// Values Splits RecordKey.Id into the values of the key columns.
+func (definition KeyDefinition) Values(id string) []string {
+	return strings.Split(id, RecordIdSeparator)
+}
+
+// PluckId Returns the IDs of the records in the map, sorted by the key columns in order.
+func (definition KeyDefinition) PluckId(recordMap map[RecordKey]string) []string {
+	firstColumn := definition.columns()[0]
+
+	var ids []string
+	for mapKey := range recordMap {
+		if mapKey.Key == firstColumn {
+			ids = append(ids, mapKey.Id)
+		}
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		return definition.less(ids[i], ids[j])
+	})
+
+	return ids
+}
+
+func (definition KeyDefinition) less(a string, b string) bool {
+	aValues := definition.Values(a)
+	bValues := definition.Values(b)
+	for i := 0; i < len(aValues) && i < len(bValues); i++ {
+		if aValues[i] == bValues[i] {
+			continue
+		}
+		if definition.Type == IntKey {
+			aNumber, _ := strconv.Atoi(aValues[i])
+			bNumber, _ := strconv.Atoi(bValues[i])
+			return aNumber < bNumber
+		}
+		return aValues[i] < bValues[i]
+	}
+
+	return len(aValues) < len(bValues)
+}
+
+// isIdColumn Returns true for the integer "id" column of map[Key]string.
+func (definition KeyDefinition) isIdColumn() bool {
+	columns := definition.columns()
+
+	return definition.Type == IntKey && len(columns) == 1 && columns[0] == "id"
+}
+
+// errorId Returns the Id and RecordId of the errors. RecordId is set instead of Id unless the key is the integer "id" column.
+func (definition KeyDefinition) errorId(id string) (int, string) {
+	if definition.isIdColumn() {
+		number, _ := strconv.Atoi(id)
+		return number, ""
+	}
+
+	return 0, id
+}
+
+func (separatedValue *SeparatedValue) LoadRecordMap(filePath string, definition KeyDefinition, filterNames []string, isColumnExclusion bool) map[RecordKey]string {
+	result, err := separatedValue.LoadRecordMapE(filePath, definition, filterNames, isColumnExclusion)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return result
+}
+
+// LoadRecordMapE Same as LoadMapE, but the records are identified by the columns of the definition.
+// With IntKey, a value that is not an integer is an InvalidIDError, and the values are normalized (e.g. "007" is "7").
+func (separatedValue *SeparatedValue) LoadRecordMapE(filePath string, definition KeyDefinition, filterNames []string, isColumnExclusion bool) (map[RecordKey]string, error) {
+	result, _, err := separatedValue.loadRecordMap(filePath, definition, filterNames, isColumnExclusion, nil, nil)
+
+	return result, err
+}
+
+// loadRecordMap Same as LoadRecordMapE, and also returns the line number in the file of each record.
+// baseSchema is enforced when the file has no schema of its own (see SchemaFor).
+func (separatedValue *SeparatedValue) loadRecordMap(filePath string, definition KeyDefinition, filterNames []string, isColumnExclusion bool, baseSchema *Schema, collector *collector) (map[RecordKey]string, map[string]int, error) {
+	if !supportFile.Exists(filePath) {
+		return make(map[RecordKey]string), make(map[string]int), nil
+	}
+
+	rows, lines, err := separatedValue.load(filePath, true, isColumnExclusion)
+	if err != nil {
+		return make(map[RecordKey]string), make(map[string]int), collector.add(err)
+	}
+
+	var filterColumnNumbers []int
+	if len(filterNames) != 0 {
+		filterColumnNumbers, err = separatedValue.filterColumnNumbers(filePath, filterNames)
+		if err != nil {
+			return make(map[RecordKey]string), make(map[string]int), collector.add(err)
+		}
+	}
+
+	schema, err := separatedValue.SchemaFor(filePath)
+	if err != nil {
+		return make(map[RecordKey]string), make(map[string]int), collector.add(err)
+	}
+	if schema == nil {
+		schema = baseSchema
+	}
+
+	return separatedValue.convertRecordMap(rows, lines, filterColumnNumbers, filePath, definition, schema, collector)
+}
+
+// convertRecordMap
+// Replacing separated value data (two-dimensional array of height and width) into a multidimensional associative array in a format
+// that facilitates direct value specification by key. The records are identified by the columns of the definition.
+// When a key column appears more than once in the header, the first one is the key.
+// When schema is not nil, every cell is checked against it and blank cells are allowed in nullable columns.
+func (separatedValue *SeparatedValue) convertRecordMap(rows [][]string, lines []int, filterColumnNumbers []int, filepath string, definition KeyDefinition, schema *Schema, collector *collector) (map[RecordKey]string, map[string]int, error) {
+	result := make(map[RecordKey]string)
+	rowNumbers := make(map[string]int)
+
+	// The first line is the key.
+	var header []string
+	if len(rows) != 0 {
+		header = rows[0]
+	}
+
+	var keyColumnNumbers []int
+	for _, column := range definition.columns() {
+		columnNumber := indexOf(header, column)
+		if columnNumber == -1 {
+			return nil, nil, collector.add(&MissingIDColumnError{FilePath: filepath, Column: column})
+		}
+		keyColumnNumbers = append(keyColumnNumbers, columnNumber)
+	}
+
+	for _, name := range schema.checkHeader(header) {
+		if err := collector.add(&SchemaError{FilePath: filepath, Row: lines[0], Column: name, Value: name, Reason: "not declared in the schema"}); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	encounteredIds := map[string]bool{}
+	for rowNumber, row := range rows {
+		if rowNumber == 0 {
+			continue
+		}
+
+		var values []string
+		isValidId := true
+		for _, columnNumber := range keyColumnNumbers {
+			value := cell(row, columnNumber)
+			if definition.Type == IntKey {
+				valueNumber, err := strconv.Atoi(value)
+				if err != nil {
+					isValidId = false
+					if err := collector.add(&InvalidIDError{FilePath: filepath, Row: lines[rowNumber], Column: header[columnNumber], Value: value}); err != nil {
+						return nil, nil, err
+					}
+					break
+				}
+				value = strconv.Itoa(valueNumber)
+			} else if value == "" {
+				isValidId = false
+				if err := collector.add(&InvalidIDError{FilePath: filepath, Row: lines[rowNumber], Column: header[columnNumber], Value: value}); err != nil {
+					return nil, nil, err
+				}
+				break
+			}
+			values = append(values, value)
+		}
+		if !isValidId {
+			continue
+		}
+
+		id := definition.Id(values...)
+		number, recordId := definition.errorId(id)
+		if encounteredIds[id] {
+			if err := collector.add(&DuplicateIDError{FilePath: filepath, Row: lines[rowNumber], Column: header[keyColumnNumbers[0]], Id: number, RecordId: recordId}); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+		encounteredIds[id] = true
+		rowNumbers[id] = lines[rowNumber]
+
+		for columnNumber, name := range header {
+			if len(filterColumnNumbers) != 0 && !array.IntContains(filterColumnNumbers, columnNumber) {
+				continue
+			}
+
+			value := cell(row, columnNumber)
+			if value == "" && !schema.isNullable(name) {
+				if err := collector.add(&BlankCellError{FilePath: filepath, Row: lines[rowNumber], Column: name, Id: number, RecordId: recordId}); err != nil {
+					return nil, nil, err
+				}
+				continue
+			}
+			if reason := schema.check(name, value); reason != "" {
+				if err := collector.add(&SchemaError{FilePath: filepath, Row: lines[rowNumber], Column: name, Value: value, Reason: reason}); err != nil {
+					return nil, nil, err
+				}
+				continue
+			}
+			result[RecordKey{id, name}] = value
+		}
+	}
+
+	return result, rowNumbers, nil
+}
+
+func (separatedValue *SeparatedValue) LoadRecordByDirectoryPath(directoryPath string, fileName string, definition KeyDefinition, baseMap map[RecordKey]string, filterNames []string) map[RecordKey]string {
+	result, err := separatedValue.LoadRecordByDirectoryPathE(directoryPath, fileName, definition, baseMap, filterNames)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return result
+}
+
+// LoadRecordByDirectoryPathE Same as LoadByDirectoryPathE, but the records are identified by the columns of the definition.
+func (separatedValue *SeparatedValue) LoadRecordByDirectoryPathE(directoryPath string, fileName string, definition KeyDefinition, baseMap map[RecordKey]string, filterNames []string) (map[RecordKey]string, error) {
+	return separatedValue.loadRecordByDirectoryPath(directoryPath, fileName, definition, baseMap, filterNames, nil, nil, nil)
+}
+
+// loadRecordByDirectoryPath baseSchema is enforced on the files that have no schema of their own.
+// When provenance is not nil, the origins of the changed cells are recorded in it.
+// Provenance is keyed by Key, so it is only given with the integer "id" column.
+func (separatedValue *SeparatedValue) loadRecordByDirectoryPath(directoryPath string, fileName string, definition KeyDefinition, baseMap map[RecordKey]string, filterNames []string, baseSchema *Schema, provenance Provenance, collector *collector) (map[RecordKey]string, error) {
+	// Avoid immediately UPDATING an INSET record within the same version (since it is an unintended update).
+	loadTypes := []string{"delete", "update", "insert"}
+	if !directory.Exist(directoryPath+"/"+loadTypes[0]+"/") &&
+		!directory.Exist(directoryPath+"/"+loadTypes[1]+"/") &&
+		!directory.Exist(directoryPath+"/"+loadTypes[2]+"/") {
+		return baseMap, collector.add(&Error{FilePath: directoryPath, Err: errors.New("Neither insert/update/delete directories were found")})
+	}
+
+	var editPositions []recordPosition
+
+	for _, loadType := range loadTypes {
+		loadTypePath := directoryPath + "/" + loadType + "/"
+		if !directory.Exist(loadTypePath) {
+			continue
+		}
+
+		separatedValueFilePaths, err := separatedValue.GetFilePathRecursive(loadTypePath)
+		if err != nil {
+			if err := collector.add(&Error{FilePath: loadTypePath, Err: errors.Wrap(err, "GetFilePathRecursiveError")}); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		for _, filePath := range separatedValueFilePaths {
+			if fileName != filepath.Base(filePath) {
+				continue
+			}
+
+			editRecordMap, rowNumbers, err := separatedValue.loadRecordMap(filePath, definition, filterNames, len(filterNames) == 0, baseSchema, collector)
+			if err != nil {
+				return nil, err
+			}
+
+			baseIds := definition.PluckId(baseMap)
+			for _, id := range definition.PluckId(editRecordMap) {
+				editPositions = append(editPositions, recordPosition{id: id, filePath: filePath, row: rowNumbers[id]})
+			}
+
+			switch loadType {
+			case "insert":
+				baseMap, err = insertRecords(baseMap, editRecordMap, definition, filePath, rowNumbers, collector)
+			case "update":
+				baseMap, err = updateRecords(baseMap, editRecordMap, definition, filePath, rowNumbers, collector)
+			case "delete":
+				baseMap, err = deleteRecords(baseMap, editRecordMap, definition, filePath, rowNumbers, collector)
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			if provenance != nil {
+				origin := Origin{FilePath: filePath, Version: filepath.Base(directoryPath), Operation: loadType}
+				provenance.record(origin, baseIds, editRecordMap, rowNumbers)
+			}
+		}
+	}
+
+	for _, position := range duplicatePositions(editPositions) {
+		number, recordId := definition.errorId(position.id)
+		if err := collector.add(&DuplicateIDError{FilePath: position.filePath, Row: position.row, Column: definition.columns()[0], Id: number, RecordId: recordId}); err != nil {
+			return nil, err
+		}
+	}
+
+	return baseMap, nil
+}
+
+func deleteRecords(baseMap map[RecordKey]string, editMap map[RecordKey]string, definition KeyDefinition, filePath string, rowNumbers map[string]int, collector *collector) (map[RecordKey]string, error) {
+	isBaseId := idSet(definition.PluckId(baseMap))
+	editIds := definition.PluckId(editMap)
+
+	var unknownIds []string
+	for _, id := range editIds {
+		if !isBaseId[id] {
+			number, recordId := definition.errorId(id)
+			if err := collector.add(&UnknownIDError{FilePath: filePath, Row: rowNumbers[id], Column: definition.columns()[0], Id: number, RecordId: recordId, Operation: "delete"}); err != nil {
+				return nil, err
+			}
+			unknownIds = append(unknownIds, id)
+		}
+	}
+	editMap = exceptIds(editMap, unknownIds)
+
+	for key := range editMap {
+		delete(baseMap, key)
+	}
+
+	return baseMap, nil
+}
+
+func insertRecords(baseMap map[RecordKey]string, editMap map[RecordKey]string, definition KeyDefinition, filePath string, rowNumbers map[string]int, collector *collector) (map[RecordKey]string, error) {
+	isBaseId := idSet(definition.PluckId(baseMap))
+	editIds := definition.PluckId(editMap)
+
+	var existingIds []string
+	for _, id := range editIds {
+		if isBaseId[id] {
+			number, recordId := definition.errorId(id)
+			if err := collector.add(&ExistingIDError{FilePath: filePath, Row: rowNumbers[id], Column: definition.columns()[0], Id: number, RecordId: recordId}); err != nil {
+				return nil, err
+			}
+			existingIds = append(existingIds, id)
+		}
+	}
+	editMap = exceptIds(editMap, existingIds)
+
+	result := make(map[RecordKey]string)
+
+	for mapKey, value := range baseMap {
+		result[mapKey] = value
+	}
+	for mapKey, value := range editMap {
+		result[mapKey] = value
+	}
+
+	return result, nil
+}
+
+func updateRecords(baseMap map[RecordKey]string, editMap map[RecordKey]string, definition KeyDefinition, filePath string, rowNumbers map[string]int, collector *collector) (map[RecordKey]string, error) {
+	isBaseId := idSet(definition.PluckId(baseMap))
+	editIds := definition.PluckId(editMap)
+
+	var unknownIds []string
+	for _, id := range editIds {
+		if !isBaseId[id] {
+			number, recordId := definition.errorId(id)
+			if err := collector.add(&UnknownIDError{FilePath: filePath, Row: rowNumbers[id], Column: definition.columns()[0], Id: number, RecordId: recordId, Operation: "update"}); err != nil {
+				return nil, err
+			}
+			unknownIds = append(unknownIds, id)
+		}
+	}
+	editMap = exceptIds(editMap, unknownIds)
+
+	baseMap, err := deleteRecords(baseMap, editMap, definition, filePath, rowNumbers, collector)
+	if err != nil {
+		return nil, err
+	}
+
+	return insertRecords(baseMap, editMap, definition, filePath, rowNumbers, collector)
+}
+
+// recordPosition Where a record of a version directory is written.
+type recordPosition struct {
+	id       string
+	filePath string
+	row      int
+}
+
+// duplicatePositions Returns the second appearance of the IDs that appear more than once, in order.
+func duplicatePositions(positions []recordPosition) []recordPosition {
+	var result []recordPosition
+	encountered := map[string]int{}
+	for _, position := range positions {
+		encountered[position.id]++
+		if encountered[position.id] == 2 {
+			result = append(result, position)
+		}
+	}
+
+	return result
+}
+
+// exceptIds Returns a copy of the map without the records of the specified IDs.
+func exceptIds(recordMap map[RecordKey]string, ids []string) map[RecordKey]string {
+	if len(ids) == 0 {
+		return recordMap
+	}
+
+	isExcepted := idSet(ids)
+	result := make(map[RecordKey]string)
+	for mapKey, value := range recordMap {
+		if !isExcepted[mapKey.Id] {
+			result[mapKey] = value
+		}
+	}
+
+	return result
+}
+
+// idSet Returns the IDs as a set, so that looking one up does not scan the slice.
+func idSet(ids []string) map[string]bool {
+	result := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		result[id] = true
+	}
+
+	return result
+}
+
+// recordMap Converts map[Key]string into the map of the integer "id" column.
+func recordMap(separatedValueMap map[Key]string) map[RecordKey]string {
+	if separatedValueMap == nil {
+		return nil
+	}
+
+	result := make(map[RecordKey]string, len(separatedValueMap))
+	for mapKey, value := range separatedValueMap {
+		result[RecordKey{Id: strconv.Itoa(mapKey.Id), Key: mapKey.Key}] = value
+	}
+
+	return result
+}
+
+// keyMap The inverse of recordMap.
+func keyMap(recordMap map[RecordKey]string) map[Key]string {
+	if recordMap == nil {
+		return nil
+	}
+
+	result := make(map[Key]string, len(recordMap))
+	for mapKey, value := range recordMap {
+		id, _ := strconv.Atoi(mapKey.Id)
+		result[Key{Id: id, Key: mapKey.Key}] = value
+	}
+
+	return result
+}
@@ -0,0 +1,92 @@
+package separated_value
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestLoadRecordMapE(t *testing.T) {
+	var separatedValue SeparatedValue
+	separatedValue.Init("csv", ".csv")
+	definition := KeyDefinition{Columns: []string{"stage_id", "wave"}, Type: IntKey}
+
+	got, err := separatedValue.LoadRecordMapE("test/stage_wave.csv", definition, []string{}, true)
+	if err != nil {
+		t.Fatalf("LoadRecordMapE() error = %v", err)
+	}
+	if value := got[RecordKey{Id: definition.Id("1", "10"), Key: "enemy"}]; value != "dragon" {
+		t.Errorf("LoadRecordMapE() enemy = %v, want dragon", value)
+	}
+
+	wantIds := []string{definition.Id("1", "2"), definition.Id("1", "10"), definition.Id("2", "1")}
+	if ids := definition.PluckId(got); !reflect.DeepEqual(ids, wantIds) {
+		t.Errorf("PluckId() = %q, want %q", ids, wantIds)
+	}
+}
+
+func TestInvalidIDError(t *testing.T) {
+	var separatedValue SeparatedValue
+	separatedValue.Init("csv", ".csv")
+
+	var invalidIDError *InvalidIDError
+	_, err := separatedValue.LoadMapE("test/invalid_id.csv", []string{}, true)
+	if !errors.As(err, &invalidIDError) || invalidIDError.Value != "abc" || invalidIDError.Row != 3 {
+		t.Errorf("LoadMapE() error = %v", err)
+	}
+
+	got, err := separatedValue.LoadRecordMapE("test/invalid_id.csv", KeyDefinition{Type: StringKey}, []string{}, true)
+	if err != nil {
+		t.Fatalf("LoadRecordMapE() error = %v", err)
+	}
+	if value := got[RecordKey{Id: "abc", Key: "name"}]; value != "bbb" {
+		t.Errorf("LoadRecordMapE() name = %v, want bbb", value)
+	}
+}
+
+func TestLoadRecordByDirectoryPathE(t *testing.T) {
+	var separatedValue SeparatedValue
+	separatedValue.Init("csv", ".csv")
+	definition := KeyDefinition{Columns: []string{"code"}, Type: StringKey}
+
+	baseMap, err := separatedValue.LoadRecordMapE("test/record/item.csv", definition, []string{}, true)
+	if err != nil {
+		t.Fatalf("LoadRecordMapE() error = %v", err)
+	}
+
+	_, err = separatedValue.LoadRecordByDirectoryPathE("test/record/1_0_0_0", "item.csv", definition, baseMap, []string{})
+	var unknownIDError *UnknownIDError
+	if !errors.As(err, &unknownIDError) || unknownIDError.RecordId != "spear" || unknownIDError.Column != "code" {
+		t.Errorf("LoadRecordByDirectoryPathE() error = %v, want an UnknownIDError of spear", err)
+	}
+
+	got, err := separatedValue.ValidateRecordByDirectoryPath("test/record/1_0_0_0", "item.csv", definition, baseMap, []string{})
+	if err == nil {
+		t.Error("ValidateRecordByDirectoryPath() error = nil, want the UnknownIDError of spear")
+	}
+	want := map[RecordKey]string{
+		{Id: "sword", Key: "code"}: "sword", {Id: "sword", Key: "name"}: "Long Sword",
+		{Id: "shield", Key: "code"}: "shield", {Id: "shield", Key: "name"}: "Shield",
+		{Id: "axe", Key: "code"}: "axe", {Id: "axe", Key: "name"}: "Axe",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ValidateRecordByDirectoryPath() = %v, want %v", got, want)
+	}
+}
+
+func TestLoadRecordMapEDuplicateHeader(t *testing.T) {
+	var separatedValue SeparatedValue
+	separatedValue.Init("csv", ".csv")
+
+	// The first "id" column is the key.
+	for i := 0; i < 10; i++ {
+		got, err := separatedValue.LoadMapE("test/record/duplicate_header.csv", []string{}, true)
+		if err != nil {
+			t.Fatalf("LoadMapE() error = %v", err)
+		}
+		if ids := separatedValue.PluckId(got); !reflect.DeepEqual(ids, []int{1, 2}) {
+			t.Fatalf("PluckId() = %v, want [1 2]", ids)
+		}
+	}
+}
@@ -0,0 +1,277 @@
+package separated_value
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/stepupdream/golang-support-tool/array"
+	"github.com/stepupdream/golang-support-tool/directory"
+)
+
+// Resolver Replays the version directories (each containing insert/update/delete) on top of the base directory
+// to build the final data of every file.
+type Resolver struct {
+	separatedValue       *SeparatedValue
+	baseDirectoryPath    string
+	versionDirectoryPath string
+}
+
+// Resolved The final data of one file, the versions that changed it in the order they were applied,
+// and the origin of every cell.
+type Resolved struct {
+	FileName   string
+	Map        map[Key]string
+	Versions   []string
+	Provenance Provenance
+}
+
+func (separatedValue *SeparatedValue) NewResolver(baseDirectoryPath string, versionDirectoryPath string) *Resolver {
+	return &Resolver{
+		separatedValue:       separatedValue,
+		baseDirectoryPath:    baseDirectoryPath,
+		versionDirectoryPath: versionDirectoryPath,
+	}
+}
+
+// Versions Returns the names of the version directories in the order they are applied.
+// Names such as 1_0_10_0 are compared number by number, so 1_0_10_0 comes after 1_0_2_0.
+func (resolver *Resolver) Versions() ([]string, error) {
+	dirEntries, err := os.ReadDir(resolver.versionDirectoryPath)
+	if err != nil {
+		return nil, &Error{FilePath: resolver.versionDirectoryPath, Err: errors.Wrap(err, "ReadDirError")}
+	}
+
+	baseAbsPath, _ := filepath.Abs(resolver.baseDirectoryPath)
+
+	var versions []string
+	for _, dirEntry := range dirEntries {
+		if !dirEntry.IsDir() {
+			continue
+		}
+
+		// The base directory may be placed side by side with the version directories.
+		absPath, _ := filepath.Abs(filepath.Join(resolver.versionDirectoryPath, dirEntry.Name()))
+		if absPath == baseAbsPath {
+			continue
+		}
+
+		versions = append(versions, dirEntry.Name())
+	}
+
+	sort.SliceStable(versions, func(i, j int) bool {
+		return compareVersion(versions[i], versions[j]) < 0
+	})
+
+	return versions, nil
+}
+
+func (resolver *Resolver) Resolve(start string, end string, filterNames []string) map[string]*Resolved {
+	result, err := resolver.ResolveE(start, end, filterNames)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return result
+}
+
+// ResolveE Replays the versions from start to end and returns the final data keyed by file name.
+// As with array.SliceString, an empty start means the first version, and end is a version name, "max" or "next".
+func (resolver *Resolver) ResolveE(start string, end string, filterNames []string) (map[string]*Resolved, error) {
+	return resolver.resolve(start, end, filterNames, nil)
+}
+
+// resolve When afterVersion is not nil, it is called with the data after each version has been applied,
+// and with an empty version after the base has been loaded.
+func (resolver *Resolver) resolve(start string, end string, filterNames []string, afterVersion func(version string, result map[string]*Resolved) error) (map[string]*Resolved, error) {
+	if !directory.Exist(resolver.baseDirectoryPath) {
+		return nil, &Error{FilePath: resolver.baseDirectoryPath, Err: errors.New("The directory could not be found")}
+	}
+
+	versions, err := resolver.Versions()
+	if err != nil {
+		return nil, err
+	}
+	versions, err = resolver.selectVersions(versions, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	result, baseSchemas, err := resolver.loadBase(filterNames)
+	if err != nil {
+		return nil, err
+	}
+
+	if afterVersion != nil {
+		if err := afterVersion("", result); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, version := range versions {
+		versionPath := filepath.Join(resolver.versionDirectoryPath, version)
+		fileNames, err := resolver.fileNames(versionPath)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, fileName := range fileNames {
+			resolved, ok := result[fileName]
+			if !ok {
+				resolved = &Resolved{FileName: fileName, Map: make(map[Key]string), Provenance: make(Provenance)}
+				result[fileName] = resolved
+			}
+
+			// The version files without a schema of their own are checked against the schema of the base file.
+			resolved.Map, err = resolver.separatedValue.loadByDirectoryPath(versionPath, fileName, resolved.Map, filterNames, baseSchemas[fileName], resolved.Provenance, nil)
+			if err != nil {
+				return nil, err
+			}
+			resolved.Versions = append(resolved.Versions, version)
+		}
+
+		if afterVersion != nil {
+			if err := afterVersion(version, result); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func (resolver *Resolver) selectVersions(versions []string, start string, end string) ([]string, error) {
+	if len(versions) == 0 {
+		if start != "" {
+			return nil, &Error{FilePath: resolver.versionDirectoryPath, Err: errors.New("The specified version could not be found : " + start)}
+		}
+		return nil, nil
+	}
+
+	startIndex := 0
+	if start != "" {
+		startIndex = indexOf(versions, start)
+		if startIndex == -1 {
+			return nil, &Error{FilePath: resolver.versionDirectoryPath, Err: errors.New("The specified version could not be found : " + start)}
+		}
+	}
+
+	if end != "max" && end != "next" {
+		endIndex := indexOf(versions, end)
+		if endIndex == -1 {
+			return nil, &Error{FilePath: resolver.versionDirectoryPath, Err: errors.New("The specified version could not be found : " + end)}
+		}
+		if endIndex < startIndex {
+			return nil, &Error{FilePath: resolver.versionDirectoryPath, Err: errors.New("The end version is older than the start version : " + end)}
+		}
+	}
+
+	return array.SliceString(versions, start, end), nil
+}
+
+// loadBase Returns the data of the base files, and their schemas (see SchemaFor) keyed by file name.
+func (resolver *Resolver) loadBase(filterNames []string) (map[string]*Resolved, map[string]*Schema, error) {
+	filePaths, err := resolver.separatedValue.GetFilePathRecursive(resolver.baseDirectoryPath)
+	if err != nil {
+		return nil, nil, &Error{FilePath: resolver.baseDirectoryPath, Err: errors.Wrap(err, "GetFilePathRecursiveError")}
+	}
+
+	result := make(map[string]*Resolved)
+	schemas := make(map[string]*Schema)
+	for _, filePath := range filePaths {
+		fileName := filepath.Base(filePath)
+		if _, ok := result[fileName]; ok {
+			return nil, nil, &Error{FilePath: filePath, Err: errors.New("The file name is not unique in the base directory")}
+		}
+
+		baseMap, provenance, err := resolver.separatedValue.LoadMapWithProvenanceE(filePath, filterNames, len(filterNames) == 0)
+		if err != nil {
+			return nil, nil, err
+		}
+		result[fileName] = &Resolved{FileName: fileName, Map: baseMap, Provenance: provenance}
+
+		schemas[fileName], err = resolver.separatedValue.SchemaFor(filePath)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return result, schemas, nil
+}
+
+// fileNames Returns the names of the files edited in the version directory.
+func (resolver *Resolver) fileNames(versionPath string) ([]string, error) {
+	filePaths, err := resolver.separatedValue.GetFilePathRecursive(versionPath)
+	if err != nil {
+		return nil, &Error{FilePath: versionPath, Err: errors.Wrap(err, "GetFilePathRecursiveError")}
+	}
+
+	var fileNames []string
+	for _, filePath := range filePaths {
+		fileNames = append(fileNames, filepath.Base(filePath))
+	}
+	fileNames = array.StringUnique(fileNames)
+	sort.Strings(fileNames)
+
+	return fileNames, nil
+}
+
+// editFilePath Returns the path of the file named fileName in the insert/update/delete directory of the version.
+func (resolver *Resolver) editFilePath(version string, loadType string, fileName string) (string, error) {
+	loadTypePath := filepath.Join(resolver.versionDirectoryPath, version, loadType)
+	filePaths, err := resolver.separatedValue.GetFilePathRecursive(loadTypePath)
+	if err != nil {
+		return "", &Error{FilePath: loadTypePath, Err: errors.Wrap(err, "GetFilePathRecursiveError")}
+	}
+
+	for _, filePath := range filePaths {
+		if filepath.Base(filePath) == fileName {
+			return filePath, nil
+		}
+	}
+
+	return "", nil
+}
+
+// compareVersion Compares version names separated by "_" or ".", treating numeric parts as numbers.
+func compareVersion(a string, b string) int {
+	separator := func(r rune) bool {
+		return r == '_' || r == '.'
+	}
+	aParts := strings.FieldsFunc(a, separator)
+	bParts := strings.FieldsFunc(b, separator)
+
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		aNumber, aErr := strconv.Atoi(aParts[i])
+		bNumber, bErr := strconv.Atoi(bParts[i])
+		if aErr == nil && bErr == nil {
+			if aNumber != bNumber {
+				if aNumber < bNumber {
+					return -1
+				}
+				return 1
+			}
+			continue
+		}
+
+		if aParts[i] != bParts[i] {
+			return strings.Compare(aParts[i], bParts[i])
+		}
+	}
+
+	return len(aParts) - len(bParts)
+}
+
+func indexOf(values []string, target string) int {
+	for index, value := range values {
+		if value == target {
+			return index
+		}
+	}
+
+	return -1
+}
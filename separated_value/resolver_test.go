@@ -0,0 +1,96 @@
+package separated_value
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResolverVersions(t *testing.T) {
+	var separatedValue SeparatedValue
+	separatedValue.Init("csv", ".csv")
+	resolver := separatedValue.NewResolver("test/resolver/base", "test/resolver/versions")
+
+	got, err := resolver.Versions()
+	if err != nil {
+		t.Fatalf("Versions() error = %v", err)
+	}
+	want := []string{"1_0_1_0", "1_0_2_0", "1_0_10_0"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Versions() = %v, want %v", got, want)
+	}
+}
+
+func TestResolverResolveE(t *testing.T) {
+	type args struct {
+		start string
+		end   string
+	}
+	tests := []struct {
+		name string
+		args args
+		want map[string]*Resolved
+	}{
+		{
+			name: "max",
+			args: args{
+				start: "",
+				end:   "max",
+			},
+			want: map[string]*Resolved{
+				"item.csv": {
+					FileName: "item.csv",
+					Map: map[Key]string{
+						{Id: 1, Key: "id"}: "1", {Id: 1, Key: "name"}: "sword", {Id: 1, Key: "price"}: "300",
+						{Id: 3, Key: "id"}: "3", {Id: 3, Key: "name"}: "bow", {Id: 3, Key: "price"}: "150",
+					},
+					Versions: []string{"1_0_1_0", "1_0_10_0"},
+				},
+				"reward.csv": {
+					FileName: "reward.csv",
+					Map: map[Key]string{
+						{Id: 1, Key: "id"}: "1", {Id: 1, Key: "amount"}: "10",
+					},
+					Versions: []string{"1_0_2_0"},
+				},
+			},
+		},
+		{
+			name: "target",
+			args: args{
+				start: "",
+				end:   "1_0_1_0",
+			},
+			want: map[string]*Resolved{
+				"item.csv": {
+					FileName: "item.csv",
+					Map: map[Key]string{
+						{Id: 1, Key: "id"}: "1", {Id: 1, Key: "name"}: "sword", {Id: 1, Key: "price"}: "100",
+						{Id: 2, Key: "id"}: "2", {Id: 2, Key: "name"}: "shield", {Id: 2, Key: "price"}: "200",
+						{Id: 3, Key: "id"}: "3", {Id: 3, Key: "name"}: "bow", {Id: 3, Key: "price"}: "150",
+					},
+					Versions: []string{"1_0_1_0"},
+				},
+			},
+		},
+	}
+	var separatedValue SeparatedValue
+	separatedValue.Init("csv", ".csv")
+	resolver := separatedValue.NewResolver("test/resolver/base", "test/resolver/versions")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolver.ResolveE(tt.args.start, tt.args.end, []string{})
+			if err != nil {
+				t.Fatalf("ResolveE() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ResolveE() = %v, want %v", got, tt.want)
+			}
+			for fileName, want := range tt.want {
+				if got[fileName] == nil || got[fileName].FileName != want.FileName ||
+					!reflect.DeepEqual(got[fileName].Map, want.Map) || !reflect.DeepEqual(got[fileName].Versions, want.Versions) {
+					t.Errorf("ResolveE() %v = %v, want %v", fileName, got[fileName], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,255 @@
+package separated_value
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"github.com/stepupdream/golang-support-tool/array"
+)
+
+type ColumnType string
+
+const (
+	StringColumn   ColumnType = "string"
+	IntColumn      ColumnType = "int"
+	FloatColumn    ColumnType = "float"
+	BoolColumn     ColumnType = "bool"
+	EnumColumn     ColumnType = "enum"
+	DateTimeColumn ColumnType = "datetime"
+)
+
+// DefaultDateTimeLayout The layout of a datetime column when Layout is not specified.
+const DefaultDateTimeLayout = "2006-01-02 15:04:05"
+
+// SchemaFileSuffix The sidecar schema of item.csv is item.schema.json in the same directory.
+const SchemaFileSuffix = ".schema.json"
+
+// Column The definition of one column. Min and Max are the range of int and float values,
+// MinLength and MaxLength are the number of characters of a string value.
+type Column struct {
+	Name      string     `json:"name"`
+	Type      ColumnType `json:"type"`
+	Nullable  bool       `json:"nullable"`
+	Min       *float64   `json:"min,omitempty"`
+	Max       *float64   `json:"max,omitempty"`
+	Values    []string   `json:"values,omitempty"`
+	Pattern   string     `json:"pattern,omitempty"`
+	MinLength *int       `json:"min_length,omitempty"`
+	MaxLength *int       `json:"max_length,omitempty"`
+	Layout    string     `json:"layout,omitempty"`
+}
+
+// Schema The typed columns of a separated value file.
+// When Strict is true, a column that is not declared is also an error.
+type Schema struct {
+	Columns []Column `json:"columns"`
+	Strict  bool     `json:"strict"`
+
+	patterns map[string]*regexp.Regexp
+}
+
+// LoadSchema Reads a schema written in JSON.
+func LoadSchema(path string) (*Schema, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, &Error{FilePath: path, Err: errors.Wrap(err, "LoadSchemaReadError")}
+	}
+
+	var schema Schema
+	if err := json.Unmarshal(content, &schema); err != nil {
+		return nil, &Error{FilePath: path, Err: errors.Wrap(err, "LoadSchemaUnmarshalError")}
+	}
+	if err := schema.prepare(); err != nil {
+		return nil, &Error{FilePath: path, Err: err}
+	}
+
+	return &schema, nil
+}
+
+// SetSchema Registers the schema of the files named fileName (e.g. "item.csv").
+// LoadMap and LoadByDirectoryPath enforce it. A registered schema takes precedence over a sidecar file.
+// A copy of schema is registered, so changing schema afterwards has no effect.
+func (separatedValue *SeparatedValue) SetSchema(fileName string, schema *Schema) error {
+	prepared := *schema
+	if err := prepared.prepare(); err != nil {
+		return &Error{FilePath: fileName, Err: err}
+	}
+
+	if separatedValue.schemas == nil {
+		separatedValue.schemas = make(map[string]*Schema)
+	}
+	separatedValue.schemas[fileName] = &prepared
+
+	return nil
+}
+
+// sidecarSchemas The sidecar schemas read so far, by path. They are shared by every SeparatedValue and never changed.
+var sidecarSchemas sync.Map
+
+type sidecarSchema struct {
+	modTime time.Time
+	size    int64
+	schema  *Schema
+}
+
+// SchemaFor Returns the schema of the file (registered with SetSchema, or the sidecar file), or nil if there is none.
+// A sidecar file is read again only when its modification time or size changes. It is safe for concurrent use.
+func (separatedValue *SeparatedValue) SchemaFor(filePath string) (*Schema, error) {
+	if schema, ok := separatedValue.schemas[filepath.Base(filePath)]; ok {
+		return schema, nil
+	}
+
+	schemaPath := filepath.Clean(strings.TrimSuffix(filePath, filepath.Ext(filePath)) + SchemaFileSuffix)
+	info, err := os.Stat(schemaPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, &Error{FilePath: schemaPath, Err: errors.Wrap(err, "SchemaStatError")}
+	}
+	if cached, ok := sidecarSchemas.Load(schemaPath); ok {
+		if sidecar := cached.(sidecarSchema); sidecar.modTime.Equal(info.ModTime()) && sidecar.size == info.Size() {
+			return sidecar.schema, nil
+		}
+	}
+
+	schema, err := LoadSchema(schemaPath)
+	if err != nil {
+		return nil, err
+	}
+	sidecarSchemas.Store(schemaPath, sidecarSchema{modTime: info.ModTime(), size: info.Size(), schema: schema})
+
+	return schema, nil
+}
+
+func (schema *Schema) prepare() error {
+	schema.patterns = make(map[string]*regexp.Regexp)
+	for _, column := range schema.Columns {
+		switch column.Type {
+		case StringColumn, IntColumn, FloatColumn, BoolColumn, EnumColumn, DateTimeColumn:
+		default:
+			return errors.Errorf("Unknown column type %q : %s", column.Type, column.Name)
+		}
+
+		if column.Pattern == "" {
+			continue
+		}
+		pattern, err := regexp.Compile(column.Pattern)
+		if err != nil {
+			return errors.Wrapf(err, "Invalid pattern : %s", column.Name)
+		}
+		schema.patterns[column.Name] = pattern
+	}
+
+	return nil
+}
+
+func (schema *Schema) column(name string) (Column, bool) {
+	for _, column := range schema.Columns {
+		if column.Name == name {
+			return column, true
+		}
+	}
+
+	return Column{}, false
+}
+
+// isNullable Returns true if a blank cell is allowed in the column.
+func (schema *Schema) isNullable(name string) bool {
+	if schema == nil {
+		return false
+	}
+	column, ok := schema.column(name)
+
+	return ok && column.Nullable
+}
+
+// checkHeader Returns the columns of the header that are not declared, when the schema is strict.
+func (schema *Schema) checkHeader(header []string) []string {
+	if schema == nil || !schema.Strict {
+		return nil
+	}
+
+	var undeclared []string
+	for _, name := range header {
+		if _, ok := schema.column(name); !ok {
+			undeclared = append(undeclared, name)
+		}
+	}
+
+	return undeclared
+}
+
+// check Returns the reason why the value does not match the column, or an empty string if it matches.
+func (schema *Schema) check(name string, value string) string {
+	if schema == nil {
+		return ""
+	}
+	column, ok := schema.column(name)
+	if !ok || (value == "" && column.Nullable) {
+		return ""
+	}
+
+	switch column.Type {
+	case IntColumn:
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			return "not an int"
+		}
+		return checkRange(column, float64(number))
+	case FloatColumn:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return "not a float"
+		}
+		return checkRange(column, number)
+	case BoolColumn:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return "not a bool"
+		}
+	case EnumColumn:
+		if !array.StrContains(column.Values, value) {
+			return "not one of " + strings.Join(column.Values, ", ")
+		}
+	case DateTimeColumn:
+		layout := column.Layout
+		if layout == "" {
+			layout = DefaultDateTimeLayout
+		}
+		if _, err := time.Parse(layout, value); err != nil {
+			return "not a datetime of the layout " + layout
+		}
+	case StringColumn:
+		length := utf8.RuneCountInString(value)
+		if column.MinLength != nil && length < *column.MinLength {
+			return "shorter than " + strconv.Itoa(*column.MinLength)
+		}
+		if column.MaxLength != nil && length > *column.MaxLength {
+			return "longer than " + strconv.Itoa(*column.MaxLength)
+		}
+		if pattern, ok := schema.patterns[name]; ok && !pattern.MatchString(value) {
+			return "not matching " + column.Pattern
+		}
+	}
+
+	return ""
+}
+
+func checkRange(column Column, number float64) string {
+	if column.Min != nil && number < *column.Min {
+		return "less than " + strconv.FormatFloat(*column.Min, 'f', -1, 64)
+	}
+	if column.Max != nil && number > *column.Max {
+		return "greater than " + strconv.FormatFloat(*column.Max, 'f', -1, 64)
+	}
+
+	return ""
+}
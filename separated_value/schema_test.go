@@ -0,0 +1,120 @@
+package separated_value
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSchema(t *testing.T) {
+	var separatedValue SeparatedValue
+	separatedValue.Init("csv", ".csv")
+
+	got, err := separatedValue.ValidateMap("test/schema/item.csv", []string{}, true)
+	want := ValidationErrors{
+		&SchemaError{FilePath: "test/schema/item.csv", Row: 3, Column: "rarity", Value: "epic", Reason: "not one of common, rare"},
+		&SchemaError{FilePath: "test/schema/item.csv", Row: 3, Column: "price", Value: "-5", Reason: "less than 0"},
+		&SchemaError{FilePath: "test/schema/item.csv", Row: 3, Column: "released_at", Value: "2023-01-02", Reason: "not a datetime of the layout 2006-01-02 15:04:05"},
+	}
+	if !reflect.DeepEqual(err, want) {
+		t.Errorf("ValidateMap() error = %v, want %v", err, want)
+	}
+	if value, ok := got[Key{Id: 1, Key: "memo"}]; !ok || value != "" {
+		t.Errorf("ValidateMap() memo = %q, %v, want a blank value", value, ok)
+	}
+}
+
+func TestSetSchema(t *testing.T) {
+	var separatedValue SeparatedValue
+	separatedValue.Init("csv", ".csv")
+
+	maxLength := 3
+	err := separatedValue.SetSchema("item.csv", &Schema{Columns: []Column{
+		{Name: "name", Type: StringColumn, MaxLength: &maxLength},
+		{Name: "memo", Type: StringColumn, Nullable: true},
+	}})
+	if err != nil {
+		t.Fatalf("SetSchema() error = %v", err)
+	}
+
+	_, err = separatedValue.LoadMapE("test/schema/item.csv", []string{}, true)
+	want := &SchemaError{FilePath: "test/schema/item.csv", Row: 2, Column: "name", Value: "sword", Reason: "longer than 3"}
+	if !reflect.DeepEqual(err, want) {
+		t.Errorf("LoadMapE() error = %v, want %v", err, want)
+	}
+}
+
+func TestSchemaForBaseFile(t *testing.T) {
+	var separatedValue SeparatedValue
+	separatedValue.Init("csv", ".csv")
+
+	// The insert file has no sidecar schema, so the schema of the base file applies.
+	resolver := separatedValue.NewResolver("test/schema/resolver/base", "test/schema/resolver/versions")
+	_, err := resolver.ResolveE("", "max", []string{})
+	want := &SchemaError{FilePath: "test/schema/resolver/versions/1_0_0_0/insert/item.csv", Row: 2, Column: "price", Value: "-5", Reason: "less than 0"}
+	if !reflect.DeepEqual(err, want) {
+		t.Errorf("ResolveE() error = %v, want %v", err, want)
+	}
+
+	first, err := separatedValue.SchemaFor("test/schema/resolver/base/item.csv")
+	if err != nil {
+		t.Fatalf("SchemaFor() error = %v", err)
+	}
+	second, err := separatedValue.SchemaFor("./test/schema/resolver/base/item.csv")
+	if err != nil {
+		t.Fatalf("SchemaFor() error = %v", err)
+	}
+	if first == nil || first != second {
+		t.Errorf("SchemaFor() = %p, %p, want the same cached schema", first, second)
+	}
+}
+
+func TestSchemaForChangedFile(t *testing.T) {
+	var separatedValue SeparatedValue
+	separatedValue.Init("csv", ".csv")
+
+	directory := t.TempDir()
+	schemaPath := filepath.Join(directory, "item.schema.json")
+	if err := os.WriteFile(schemaPath, []byte(`{"columns": [{"name": "id", "type": "int"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	first, err := separatedValue.SchemaFor(filepath.Join(directory, "item.csv"))
+	if err != nil {
+		t.Fatalf("SchemaFor() error = %v", err)
+	}
+
+	if err := os.WriteFile(schemaPath, []byte(`{"columns": [{"name": "id", "type": "int"}], "strict": true}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(schemaPath, later, later); err != nil {
+		t.Fatal(err)
+	}
+	second, err := separatedValue.SchemaFor(filepath.Join(directory, "item.csv"))
+	if err != nil {
+		t.Fatalf("SchemaFor() error = %v", err)
+	}
+	if first.Strict || !second.Strict {
+		t.Errorf("SchemaFor() Strict = %v, %v, want false, true", first.Strict, second.Strict)
+	}
+}
+
+func TestSchemaForConcurrent(t *testing.T) {
+	var separatedValue SeparatedValue
+	separatedValue.Init("csv", ".csv")
+
+	var waitGroup sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			if _, err := separatedValue.LoadMapE("test/schema/item.csv", []string{}, true); err == nil {
+				t.Error("LoadMapE() error = nil, want a schema error")
+			}
+		}()
+	}
+	waitGroup.Wait()
+}
@@ -1,9 +1,6 @@
 package separated_value
 
 import (
-	"bufio"
-	"encoding/csv"
-	"io/fs"
 	"log"
 	"os"
 	"path/filepath"
@@ -16,14 +13,35 @@ import (
 	supportFile "github.com/stepupdream/golang-support-tool/file"
 )
 
+// SeparatedValue Reads and writes separated value files. Make it with New, or with Init for csv and tsv.
+// The zero value reads and writes csv.
 type SeparatedValue struct {
-	separatedType string
-	extension     string
+	extension          string
+	delimiter          rune
+	quotePolicy        QuotePolicy
+	lineEnding         LineEnding
+	isLazyQuotes       bool
+	isTrimLeadingSpace bool
+	isKeepBOM          bool
+	isOmitBOM          bool
+	readEncoding       Encoding
+	writeEncoding      Encoding
+	fieldsPerRecord    int
+	schemas            map[string]*Schema
+	loadOptions        LoadOptions
 }
 
+// Init Sets up csv, or tsv (tab separated, with lazy quotes) when separatedType is "tsv".
+//
+// Deprecated: Use New, which accepts any delimiter and validates the settings.
 func (separatedValue *SeparatedValue) Init(separatedType string, extension string) {
-	separatedValue.separatedType = separatedType
 	separatedValue.extension = extension
+	separatedValue.delimiter = ','
+	separatedValue.isLazyQuotes = false
+	if separatedType == "tsv" {
+		separatedValue.delimiter = '\t'
+		separatedValue.isLazyQuotes = true
+	}
 }
 
 // Key Make keys into structures to achieve multidimensional arrays.
@@ -37,129 +55,123 @@ func (separatedValue *SeparatedValue) GetExtension() string {
 }
 
 func (separatedValue *SeparatedValue) LoadMap(filePath string, filterNames []string, isColumnExclusion bool) map[Key]string {
-	var rows [][]string
-	if !supportFile.Exists(filePath) {
-		return make(map[Key]string)
+	result, err := separatedValue.LoadMapE(filePath, filterNames, isColumnExclusion)
+	if err != nil {
+		log.Fatal(err)
 	}
 
-	var filterColumnNumbers []int
-	rows = separatedValue.Load(filePath, true, isColumnExclusion)
-	if len(filterNames) != 0 {
-		filterColumnNumbers = separatedValue.filterColumnNumbers(filePath, filterNames)
-	}
+	return result
+}
 
-	return separatedValue.convertMap(rows, filterColumnNumbers, filePath)
+// LoadMapE Same as LoadMap, but returns an error instead of terminating the program.
+func (separatedValue *SeparatedValue) LoadMapE(filePath string, filterNames []string, isColumnExclusion bool) (map[Key]string, error) {
+	return separatedValue.loadMap(filePath, filterNames, isColumnExclusion, nil)
+}
+
+// loadMap LoadMapE with the collector. It is loadRecordMap with the integer "id" column.
+func (separatedValue *SeparatedValue) loadMap(filePath string, filterNames []string, isColumnExclusion bool, collector *collector) (map[Key]string, error) {
+	result, _, err := separatedValue.loadRecordMap(filePath, KeyDefinition{}, filterNames, isColumnExclusion, nil, collector)
+
+	return keyMap(result), err
 }
 
 // Load Reading separated value files
 func (separatedValue *SeparatedValue) Load(filepath string, isRowExclusion bool, isColumnExclusion bool) [][]string {
-	file, err := os.Open(filepath)
+	rows, err := separatedValue.LoadE(filepath, isRowExclusion, isColumnExclusion)
 	if err != nil {
-		log.Fatal("LoadSeparatedValueOpenError: ", err)
+		log.Fatal(err)
 	}
-	defer func(file *os.File) {
-		err := file.Close()
-		if err != nil {
-			log.Fatal("LoadSeparatedValueCloseError: ", err)
-		}
-	}(file)
 
-	// If BOM is included, delete the BOM
-	// https://pinzolo.github.io/2017/03/29/utf8-csv-with-bom-on-golang.html
-	reader := bufio.NewReader(file)
-	bytes, err := reader.Peek(3)
-	if err != nil {
-		log.Fatal("LoadSeparatedValueNewReaderError: ", err)
-	} else if bytes[0] == 0xEF && bytes[1] == 0xBB && bytes[2] == 0xBF {
-		_, err := reader.Discard(3)
-		if err != nil {
-			log.Fatal("SeparatedValueDiscardError: ", err)
-		}
-	}
+	return rows
+}
 
-	separatedValueReader := csv.NewReader(reader)
-	if isRowExclusion {
-		separatedValueReader.Comment = '#'
-	}
-	if separatedValue.separatedType == "tsv" {
-		separatedValueReader.Comma = '\t'
-		separatedValueReader.LazyQuotes = true
-	}
-	rows, err := separatedValueReader.ReadAll()
+// LoadE Same as Load, but returns an error instead of terminating the program.
+func (separatedValue *SeparatedValue) LoadE(filepath string, isRowExclusion bool, isColumnExclusion bool) ([][]string, error) {
+	rows, _, err := separatedValue.load(filepath, isRowExclusion, isColumnExclusion)
+
+	return rows, err
+}
+
+// load Reading separated value files together with the line number of each row in the file.
+func (separatedValue *SeparatedValue) load(filepath string, isRowExclusion bool, isColumnExclusion bool) ([][]string, []int, error) {
+	var rows [][]string
+	var lines []int
+	err := separatedValue.Each(filepath, isRowExclusion, isColumnExclusion, func(row []string, line int) error {
+		rows = append(rows, append([]string(nil), row...))
+		lines = append(lines, line)
+		return nil
+	})
 	if err != nil {
-		log.Fatal("SeparatedValueReadAllError: ", err)
+		return nil, nil, err
 	}
 
-	if isColumnExclusion {
-		return separatedValue.exclusionColumn(rows, isColumnExclusion)
+	if isColumnExclusion && len(rows) == 0 {
+		return nil, nil, &Error{FilePath: filepath, Err: errors.New("The header row could not be found")}
 	}
 
-	return rows
+	return rows, lines, nil
 }
 
-func (separatedValue *SeparatedValue) exclusionColumn(rows [][]string, isExclusion bool) [][]string {
-	var disableColumnIndexes []int
-	for index, value := range rows[0] {
-		if isExclusion && value == "#" {
-			disableColumnIndexes = append(disableColumnIndexes, index)
-		}
-	}
-
-	var newRows [][]string
-	for _, row := range rows {
-		var newRow []string
-		for index, value := range row {
-			if !array.IntContains(disableColumnIndexes, index) {
-				newRow = append(newRow, value)
-			}
-		}
-		newRows = append(newRows, newRow)
+// cell Returns the cell of the column, or a blank when the row is shorter than the header (see WithFieldsPerRecord).
+// The cells beyond the header have no column and are never read.
+func cell(row []string, columnNumber int) string {
+	if columnNumber >= len(row) {
+		return ""
 	}
 
-	return newRows
+	return row[columnNumber]
 }
 
-// convertMap
-// Replacing separated value data (two-dimensional array of height and width) into a multidimensional associative array in a format
-// that facilitates direct value specification by key.
-func (separatedValue *SeparatedValue) convertMap(rows [][]string, filterColumnNumbers []int, filepath string) map[Key]string {
-	result := make(map[Key]string)
-	keyName := map[int]string{}
-	findIdColumn := false
-	idColumnNumber := 0
-
-	for rowNumber, row := range rows {
-		for columnNumber, value := range row {
-			// The first line is the key.
-			if rowNumber == 0 {
-				if value == "id" {
-					findIdColumn = true
-					idColumnNumber = columnNumber
-				}
-				keyName[columnNumber] = value
-				continue
-			}
+// ConvertRows The inverse of LoadMap. Converts the map into rows sorted by id, with a header row.
+// header gives the column order. Columns not in header (or all of them when header is empty) follow "id" in name order.
+func (separatedValue *SeparatedValue) ConvertRows(separatedValueMap map[Key]string, header []string) [][]string {
+	return convertRows(separatedValueMap, separatedValue.PluckId(separatedValueMap), header)
+}
 
-			if len(filterColumnNumbers) != 0 && !array.IntContains(filterColumnNumbers, columnNumber) {
-				continue
-			}
+// convertRows Converts the records of the specified IDs into rows with a header row.
+func convertRows(separatedValueMap map[Key]string, ids []int, header []string) [][]string {
+	columnNames := map[string]bool{}
+	for mapKey := range separatedValueMap {
+		if array.IntContains(ids, mapKey.Id) {
+			columnNames[mapKey.Key] = true
+		}
+	}
 
-			id, _ := strconv.Atoi(row[idColumnNumber])
-			if _, flg := result[Key{id, keyName[columnNumber]}]; flg {
-				log.Fatal("ID is not unique : ", filepath)
-			}
-			if value == "" {
-				log.Fatal("Blank space is prohibited because it is impossible to determine if you forgot to enter the information. : ", filepath, " rowNumber : ", rowNumber)
-			}
-			result[Key{id, keyName[columnNumber]}] = value
+	var columns []string
+	for _, name := range header {
+		if columnNames[name] && !array.StrContains(columns, name) {
+			columns = append(columns, name)
 		}
 	}
 
-	if !findIdColumn {
-		log.Fatal("Separated value without ID column cannot be read : ", filepath)
+	var rest []string
+	for name := range columnNames {
+		if !array.StrContains(columns, name) {
+			rest = append(rest, name)
+		}
+	}
+	sort.Slice(rest, func(i, j int) bool {
+		if rest[i] == "id" || rest[j] == "id" {
+			return rest[i] == "id"
+		}
+		return rest[i] < rest[j]
+	})
+	columns = append(columns, rest...)
+
+	rows := [][]string{columns}
+	for _, id := range ids {
+		row := make([]string, 0, len(columns))
+		for _, column := range columns {
+			value, ok := separatedValueMap[Key{Id: id, Key: column}]
+			if !ok && column == "id" {
+				value = strconv.Itoa(id)
+			}
+			row = append(row, value)
+		}
+		rows = append(rows, row)
 	}
 
-	return result
+	return rows
 }
 
 func (separatedValue *SeparatedValue) PluckId(separatedValueMap map[Key]string) []int {
@@ -188,19 +200,12 @@ func (separatedValue *SeparatedValue) PluckKey(separatedValueMap map[Key]string,
 	return values
 }
 
+// GetFilePathRecursive Returns the files with the extension under the path.
+// Lock files, hidden files and the files matched by the ignore files are skipped (see directory.WalkFiles).
 func (separatedValue *SeparatedValue) GetFilePathRecursive(path string) ([]string, error) {
 	var paths []string
 
-	// Recursively retrieve directories and files. (use WalkDir since Walk is now deprecated)
-	err := filepath.WalkDir(path, func(path string, info fs.DirEntry, err error) error {
-		if err != nil {
-			return errors.Wrap(err, "failed filepath.WalkDir")
-		}
-
-		if info.IsDir() {
-			return nil
-		}
-
+	err := directory.WalkFiles(path, func(path string) error {
 		extension := filepath.Ext(path)
 		if extension != separatedValue.extension {
 			return nil
@@ -215,114 +220,128 @@ func (separatedValue *SeparatedValue) GetFilePathRecursive(path string) ([]strin
 }
 
 func (separatedValue *SeparatedValue) NewFile(path string, rows [][]string) {
+	err := separatedValue.NewFileE(path, rows)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// NewFileE Same as NewFile, but returns an error instead of terminating the program.
+func (separatedValue *SeparatedValue) NewFileE(path string, rows [][]string) (err error) {
 	// create allows you to create a new file and overwrite a new file.
 	separatedFile, err := os.Create(path)
 	if err != nil {
-		log.Fatalln(err)
+		return &Error{FilePath: path, Err: errors.Wrap(err, "NewSeparatedValueCreateError")}
 	}
+	defer func(separatedFile *os.File) {
+		closeErr := separatedFile.Close()
+		if closeErr != nil && err == nil {
+			err = &Error{FilePath: path, Err: errors.Wrap(closeErr, "NewSeparatedValueCloseError")}
+		}
+	}(separatedFile)
 
 	// Make it with BOM to avoid garbled characters.
-	_, err = separatedFile.Write([]byte{0xEF, 0xBB, 0xBF})
+	writer, err := separatedValue.encode(separatedFile)
 	if err != nil {
-		log.Fatal(err)
+		return &Error{FilePath: path, Err: errors.Wrap(err, "NewSeparatedValueWriteError")}
 	}
 
-	writer := csv.NewWriter(separatedFile)
-	defer writer.Flush()
-
-	if separatedValue.separatedType == "tsv" {
-		writer.Comma = '\t'
+	if err := separatedValue.writeRows(writer, rows); err != nil {
+		return &Error{FilePath: path, Err: errors.Wrap(err, "NewSeparatedValueWriteError")}
 	}
-
-	if err := writer.WriteAll(rows); err != nil {
-		log.Fatal(err)
+	if err := writer.Close(); err != nil {
+		return &Error{FilePath: path, Err: errors.Wrap(err, "NewSeparatedValueWriteError")}
 	}
-}
 
-func (separatedValue *SeparatedValue) delete(baseMap map[Key]string, editMap map[Key]string, filePath string) map[Key]string {
-	baseIds := separatedValue.PluckId(baseMap)
+	return nil
+}
 
-	for key, _ := range editMap {
-		if key.Key == "id" {
-			if !array.IntContains(baseIds, key.Id) {
-				log.Fatal("Attempted to delete a non-existent ID : id ", key.Id, " ", filePath)
-			}
-		}
-		delete(baseMap, Key{Id: key.Id, Key: key.Key})
+func (separatedValue *SeparatedValue) NewFileFromMap(path string, separatedValueMap map[Key]string, header []string) {
+	err := separatedValue.NewFileFromMapE(path, separatedValueMap, header)
+	if err != nil {
+		log.Fatal(err)
 	}
-
-	return baseMap
 }
 
-func (separatedValue *SeparatedValue) insert(baseMap map[Key]string, editMap map[Key]string, filePath string) map[Key]string {
-	baseIds := separatedValue.PluckId(baseMap)
-	editIds := separatedValue.PluckId(editMap)
-
-	for _, id := range editIds {
-		if array.IntContains(baseIds, id) {
-			log.Fatal("Tried to do an insert on an existing ID : id ", id, " ", filePath)
+// NewFileFromMapE Writes the map with ConvertRows. When header is empty and the file already exists,
+// the header of the existing file is used so that the column order of a load-modify-save round trip is preserved.
+func (separatedValue *SeparatedValue) NewFileFromMapE(path string, separatedValueMap map[Key]string, header []string) error {
+	if len(header) == 0 && supportFile.Exists(path) {
+		var err error
+		header, err = separatedValue.LoadHeaderE(path)
+		if err != nil {
+			return err
 		}
 	}
 
-	result := make(map[Key]string)
+	return separatedValue.NewFileE(path, separatedValue.ConvertRows(separatedValueMap, header))
+}
 
-	for mapKey, value := range baseMap {
-		result[Key{Id: mapKey.Id, Key: mapKey.Key}] = value
+// LoadHeaderE Returns the header row (the first row that is not a comment) of the file.
+func (separatedValue *SeparatedValue) LoadHeaderE(filePath string) ([]string, error) {
+	rows, err := separatedValue.LoadE(filePath, true, false)
+	if err != nil {
+		return nil, err
 	}
-	for mapKey, value := range editMap {
-		result[Key{Id: mapKey.Id, Key: mapKey.Key}] = value
+	if len(rows) == 0 {
+		return nil, &Error{FilePath: filePath, Err: errors.New("The header row could not be found")}
 	}
 
-	return result
+	return rows[0], nil
 }
 
-func (separatedValue *SeparatedValue) update(baseMap map[Key]string, editMap map[Key]string, filePath string) map[Key]string {
-	baseIds := separatedValue.PluckId(baseMap)
-	editIds := separatedValue.PluckId(editMap)
-	for _, id := range editIds {
-		if !array.IntContains(baseIds, id) {
-			log.Fatal("Tried to update a non-existent ID : id ", id, " ", filePath)
-		}
+func (separatedValue *SeparatedValue) LoadFileFirstContent(directoryPath string, fileName string) string {
+	result, err := separatedValue.LoadFileFirstContentE(directoryPath, fileName)
+	if err != nil {
+		log.Fatal(err)
 	}
 
-	baseMap = separatedValue.delete(baseMap, editMap, filePath)
-	baseMap = separatedValue.insert(baseMap, editMap, filePath)
-
-	return baseMap
+	return result
 }
 
-func (separatedValue *SeparatedValue) LoadFileFirstContent(directoryPath string, fileName string) string {
+// LoadFileFirstContentE Same as LoadFileFirstContent, but returns an error instead of terminating the program.
+func (separatedValue *SeparatedValue) LoadFileFirstContentE(directoryPath string, fileName string) (string, error) {
 	if !directory.Exist(directoryPath) {
-		log.Fatal("The directory could not be found : ", directoryPath)
+		return "", &Error{FilePath: directoryPath, Err: errors.New("The directory could not be found")}
 	}
 	baseSeparatedValueFilePaths, err := separatedValue.GetFilePathRecursive(directoryPath)
 	if err != nil {
-		log.Fatal("LoadFileFirstContentError: ", err)
+		return "", &Error{FilePath: directoryPath, Err: errors.Wrap(err, "LoadFileFirstContentError")}
 	}
 
 	if len(baseSeparatedValueFilePaths) == 0 {
-		return ""
+		return "", nil
 	}
 
 	var result string
 	for _, path := range baseSeparatedValueFilePaths {
 		if filepath.Base(path) == fileName {
-			rows := separatedValue.Load(path, true, false)
-			row := rows[0]
-			result = row[0]
+			rows, err := separatedValue.LoadE(path, true, false)
+			if err != nil {
+				return "", err
+			}
+			if len(rows) != 0 && len(rows[0]) != 0 {
+				result = rows[0][0]
+			}
 			break
 		}
 	}
 
 	if result == "" {
-		log.Fatal("The content could not be found : ", fileName)
+		return "", &Error{FilePath: filepath.Join(directoryPath, fileName), Err: errors.New("The content could not be found")}
 	}
 
-	return result
+	return result, nil
 }
 
-func (separatedValue *SeparatedValue) filterColumnNumbers(filepath string, filterColumnNames []string) []int {
-	rows := separatedValue.Load(filepath, true, false)
+func (separatedValue *SeparatedValue) filterColumnNumbers(filepath string, filterColumnNames []string) ([]int, error) {
+	rows, err := separatedValue.LoadE(filepath, true, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
 
 	// Get the column number of the column to filter
 	var columnNumbers []int
@@ -332,60 +351,30 @@ func (separatedValue *SeparatedValue) filterColumnNumbers(filepath string, filte
 		}
 	}
 
-	return columnNumbers
+	return columnNumbers, nil
 }
 
 func (separatedValue *SeparatedValue) LoadByDirectoryPath(directoryPath string, fileName string, baseMap map[Key]string, filterNames []string) map[Key]string {
-	// Avoid immediately UPDATING an INSET record within the same version (since it is an unintended update).
-	loadTypes := []string{"delete", "update", "insert"}
-	if !directory.Exist(directoryPath+"/"+loadTypes[0]+"/") &&
-		!directory.Exist(directoryPath+"/"+loadTypes[1]+"/") &&
-		!directory.Exist(directoryPath+"/"+loadTypes[2]+"/") {
-		log.Fatal("Neither insert/update/delete directories were found : ", directoryPath)
+	result, err := separatedValue.LoadByDirectoryPathE(directoryPath, fileName, baseMap, filterNames)
+	if err != nil {
+		log.Fatal(err)
 	}
 
-	var editIdsAll []int
-
-	for _, loadType := range loadTypes {
-		loadTypePath := directoryPath + "/" + loadType + "/"
-		if !directory.Exist(loadTypePath) {
-			continue
-		}
-
-		separatedValueFilePaths, err := separatedValue.GetFilePathRecursive(loadTypePath)
-		if err != nil {
-			log.Fatal("GetFilePathRecursiveError: ", err)
-		}
-
-		for _, filePath := range separatedValueFilePaths {
-			if fileName != filepath.Base(filePath) {
-				continue
-			}
-
-			var editSeparatedValueMap map[Key]string
-			if len(filterNames) != 0 {
-				editSeparatedValueMap = separatedValue.LoadMap(filePath, filterNames, false)
-			} else {
-				editSeparatedValueMap = separatedValue.LoadMap(filePath, filterNames, true)
-			}
-
-			editIds := separatedValue.PluckId(editSeparatedValueMap)
-			editIdsAll = append(editIdsAll, editIds...)
+	return result
+}
 
-			switch loadType {
-			case "insert":
-				baseMap = separatedValue.insert(baseMap, editSeparatedValueMap, filePath)
-			case "update":
-				baseMap = separatedValue.update(baseMap, editSeparatedValueMap, filePath)
-			case "delete":
-				baseMap = separatedValue.delete(baseMap, editSeparatedValueMap, filePath)
-			}
-		}
-	}
+// LoadByDirectoryPathE Same as LoadByDirectoryPath, but returns an error instead of terminating the program.
+// The insert/update/delete files are checked against the schema registered with SetSchema or their own sidecar file.
+// The sidecar file next to the base file is not found from the version directory: register it with SetSchema,
+// or use Resolver, which applies it to the version files that have no schema of their own.
+func (separatedValue *SeparatedValue) LoadByDirectoryPathE(directoryPath string, fileName string, baseMap map[Key]string, filterNames []string) (map[Key]string, error) {
+	return separatedValue.loadByDirectoryPath(directoryPath, fileName, baseMap, filterNames, nil, nil, nil)
+}
 
-	if !array.IsArrayUnique(editIdsAll) {
-		log.Fatal("ID is not unique : ", directoryPath, " ", fileName)
-	}
+// loadByDirectoryPath baseSchema is enforced on the files that have no schema of their own.
+// When provenance is not nil, the origins of the changed cells are recorded in it.
+func (separatedValue *SeparatedValue) loadByDirectoryPath(directoryPath string, fileName string, baseMap map[Key]string, filterNames []string, baseSchema *Schema, provenance Provenance, collector *collector) (map[Key]string, error) {
+	result, err := separatedValue.loadRecordByDirectoryPath(directoryPath, fileName, KeyDefinition{}, recordMap(baseMap), filterNames, baseSchema, provenance, collector)
 
-	return baseMap
+	return keyMap(result), err
 }
@@ -0,0 +1,268 @@
+package separated_value
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultSniffLines The number of lines Sniff examines when lineCount is 0 or less.
+const DefaultSniffLines = 20
+
+// SniffDelimiters The delimiters Sniff chooses from, in order of preference when they fit equally well.
+var SniffDelimiters = []rune{',', '\t', ';', '|'}
+
+// Sniffed What Sniff guessed about a file. Quote is the double or single quote that encloses fields, or 0 when none does.
+type Sniffed struct {
+	Delimiter rune
+	Quote     rune
+	HasHeader bool
+	HasId     bool
+	Encoding  Encoding
+	Extension string
+}
+
+func Sniff(filePath string, lineCount int) Sniffed {
+	sniffed, err := SniffE(filePath, lineCount)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return sniffed
+}
+
+// SniffE Examines the first lineCount lines of the file (blank lines and "#" comments excluded) and guesses
+// the encoding, the delimiter (the one of SniffDelimiters that splits the lines into the same number of fields most often),
+// the quote, and whether the first row is a header with an "id" column.
+func SniffE(filePath string, lineCount int) (Sniffed, error) {
+	if lineCount <= 0 {
+		lineCount = DefaultSniffLines
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return Sniffed{}, &Error{FilePath: filePath, Err: errors.Wrap(err, "SniffOpenError")}
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	sample, err := reader.Peek(detectionSize)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return Sniffed{}, &Error{FilePath: filePath, Err: errors.Wrap(err, "SniffReadError")}
+	}
+	encoding, err := DetectEncodingE(sample)
+	if err != nil {
+		return Sniffed{}, &Error{FilePath: filePath, Err: err}
+	}
+	sniffed := Sniffed{Encoding: encoding, Extension: filepath.Ext(filePath)}
+
+	decoder := &SeparatedValue{readEncoding: sniffed.Encoding}
+	decodedReader, err := decoder.decode(reader)
+	if err != nil {
+		return Sniffed{}, &Error{FilePath: filePath, Err: err}
+	}
+
+	var lines []string
+	for len(lines) < lineCount {
+		line, err := decodedReader.ReadString('\n')
+		if len(lines) == 0 {
+			line = strings.TrimPrefix(line, string(utf8BOM))
+		}
+		if trimmed := strings.TrimRight(line, "\r\n"); trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			lines = append(lines, trimmed)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Sniffed{}, &Error{FilePath: filePath, Err: errors.Wrap(err, "SniffReadError")}
+		}
+	}
+
+	sniffed.Delimiter = sniffDelimiter(lines)
+	sniffed.Quote = sniffQuote(lines, sniffed.Delimiter)
+
+	rows := parseSample(lines, sniffed.Delimiter)
+	sniffed.HasHeader = sniffHeader(rows)
+	if sniffed.HasHeader {
+		for _, name := range rows[0] {
+			if name == "id" {
+				sniffed.HasId = true
+			}
+		}
+	}
+
+	return sniffed, nil
+}
+
+// Options Returns the options of New that read the file as sniffed.
+// Tab separated files and files whose fields are not quoted with '"' are read with lazy quotes.
+func (sniffed Sniffed) Options() []Option {
+	options := []Option{WithDelimiter(sniffed.Delimiter), WithReadEncoding(sniffed.Encoding)}
+	if sniffed.Extension != "" {
+		options = append(options, WithExtension(sniffed.Extension))
+	}
+	if sniffed.Delimiter == '\t' || sniffed.Quote != '"' {
+		options = append(options, WithLazyQuotes(true))
+	}
+
+	return options
+}
+
+func NewSniffed(filePath string, lineCount int, options ...Option) (*SeparatedValue, Sniffed) {
+	separatedValue, sniffed, err := NewSniffedE(filePath, lineCount, options...)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return separatedValue, sniffed
+}
+
+// NewSniffedE Sniffs the file and makes a SeparatedValue that reads it. options are applied after the sniffed ones,
+// so they can override them.
+func NewSniffedE(filePath string, lineCount int, options ...Option) (*SeparatedValue, Sniffed, error) {
+	sniffed, err := SniffE(filePath, lineCount)
+	if err != nil {
+		return nil, Sniffed{}, err
+	}
+
+	separatedValue, err := New(append(sniffed.Options(), options...)...)
+	if err != nil {
+		return nil, Sniffed{}, err
+	}
+
+	return separatedValue, sniffed, nil
+}
+
+// sniffDelimiter Chooses the delimiter that gives the same number of fields (more than one) on the most rows,
+// then the most fields. A file that no delimiter splits is a single column of csv.
+func sniffDelimiter(lines []string) rune {
+	result := ','
+	bestRows, bestFields := 0, 1
+	for _, delimiter := range SniffDelimiters {
+		counts := map[int]int{}
+		for _, row := range parseSample(lines, delimiter) {
+			counts[len(row)]++
+		}
+
+		for fields, rows := range counts {
+			if fields <= 1 {
+				continue
+			}
+			if rows > bestRows || (rows == bestRows && fields > bestFields) {
+				result, bestRows, bestFields = delimiter, rows, fields
+			}
+		}
+	}
+
+	return result
+}
+
+// sniffQuote Returns the character that encloses a field, looking at the fields split naively by the delimiter.
+func sniffQuote(lines []string, delimiter rune) rune {
+	var result rune
+	for _, line := range lines {
+		for _, field := range strings.Split(line, string(delimiter)) {
+			field = strings.TrimSpace(field)
+			if len(field) < 2 {
+				continue
+			}
+			switch {
+			case field[0] == '"':
+				return '"'
+			case field[0] == '\'' && field[len(field)-1] == '\'':
+				result = '\''
+			}
+		}
+	}
+
+	return result
+}
+
+// sniffHeader The first row is a header when it has a column named "id", or when its cells look different from
+// the cells of the other rows: a text above numbers, or a length other than the fixed length of the column.
+func sniffHeader(rows [][]string) bool {
+	if len(rows) == 0 {
+		return false
+	}
+	header := rows[0]
+	for _, name := range header {
+		if strings.EqualFold(strings.TrimSpace(name), "id") {
+			return true
+		}
+	}
+
+	if len(rows) == 1 {
+		names := map[string]bool{}
+		for _, name := range header {
+			if name == "" || isNumeric(name) || names[name] {
+				return false
+			}
+			names[name] = true
+		}
+		return true
+	}
+
+	votes := 0
+	for columnNumber, name := range header {
+		isAllNumeric := true
+		length := -1
+		for _, row := range rows[1:] {
+			if columnNumber >= len(row) {
+				continue
+			}
+			if !isNumeric(row[columnNumber]) {
+				isAllNumeric = false
+			}
+			if length == -1 {
+				length = len(row[columnNumber])
+			} else if length != len(row[columnNumber]) {
+				length = -2
+			}
+		}
+
+		switch {
+		case isAllNumeric && !isNumeric(name):
+			votes++
+		case isAllNumeric:
+			votes--
+		case length >= 0 && length != len(name):
+			votes++
+		case length >= 0:
+			votes--
+		}
+	}
+
+	return votes > 0
+}
+
+func parseSample(lines []string, delimiter rune) [][]string {
+	reader := csv.NewReader(bytes.NewBufferString(strings.Join(lines, "\n")))
+	reader.Comma = delimiter
+	reader.LazyQuotes = true
+	reader.FieldsPerRecord = -1
+
+	var rows [][]string
+	for {
+		row, err := reader.Read()
+		// The last line of the sample may end in a quoted field.
+		if err != nil {
+			return rows
+		}
+		rows = append(rows, row)
+	}
+}
+
+func isNumeric(value string) bool {
+	_, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+
+	return err == nil
+}
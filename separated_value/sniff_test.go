@@ -0,0 +1,136 @@
+package separated_value
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSniffE(t *testing.T) {
+	tests := []struct {
+		name     string
+		fileName string
+		content  []byte
+		want     Sniffed
+	}{
+		{
+			name:     "csv with id",
+			fileName: "item.csv",
+			content:  []byte("\xEF\xBB\xBFid,name,price\n1,sword,100\n2,\"shield, large\",250\n"),
+			want:     Sniffed{Delimiter: ',', Quote: '"', HasHeader: true, HasId: true, Encoding: UTF8, Extension: ".csv"},
+		},
+		{
+			name:     "tsv without id",
+			fileName: "item.tsv",
+			content:  []byte("code\tname\tprice\n10\tsword, short\t100\n20\tshield\t250\n"),
+			want:     Sniffed{Delimiter: '\t', HasHeader: true, Encoding: UTF8, Extension: ".tsv"},
+		},
+		{
+			name:     "semicolon without header",
+			fileName: "item.txt",
+			content:  []byte("# comment\n1;sword;100\n2;shield;250\n3;bow;80\n"),
+			want:     Sniffed{Delimiter: ';', Encoding: UTF8, Extension: ".txt"},
+		},
+		{
+			name:     "pipe with single quotes",
+			fileName: "item.txt",
+			content:  []byte("label|price\n'sword'|100\n'shield'|250\n"),
+			want:     Sniffed{Delimiter: '|', Quote: '\'', HasHeader: true, Encoding: UTF8, Extension: ".txt"},
+		},
+		{
+			name:     "shift_jis",
+			fileName: "item.csv",
+			content:  []byte("id,name\n1,\x8C\x95\n"),
+			want:     Sniffed{Delimiter: ',', HasHeader: true, HasId: true, Encoding: ShiftJIS, Extension: ".csv"},
+		},
+		{
+			name:     "utf-16le",
+			fileName: "item.tsv",
+			content:  []byte("\xFF\xFEi\x00d\x00\t\x00n\x00\n\x001\x00\t\x00a\x00\n\x00"),
+			want:     Sniffed{Delimiter: '\t', HasHeader: true, HasId: true, Encoding: UTF16LE, Extension: ".tsv"},
+		},
+		{
+			name:     "single column of text",
+			fileName: "item.csv",
+			content:  []byte("name\nsword\nshield\n"),
+			want:     Sniffed{Delimiter: ',', Encoding: UTF8, Extension: ".csv"},
+		},
+		{
+			name:     "header only",
+			fileName: "item.csv",
+			content:  []byte("id,name\n"),
+			want:     Sniffed{Delimiter: ',', HasHeader: true, HasId: true, Encoding: UTF8, Extension: ".csv"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.fileName)
+			if err := os.WriteFile(path, tt.content, 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := SniffE(path, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SniffE() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSniffELineCount(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "item.csv")
+	// Only the first two lines are examined, so the semicolons below do not count.
+	content := "id,name\n1,sword\n2;a;b;c\n3;a;b;c\n4;a;b;c\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := SniffE(path, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Delimiter != ',' {
+		t.Errorf("SniffE() Delimiter = %q, want ','", got.Delimiter)
+	}
+}
+
+func TestSniffENotFound(t *testing.T) {
+	if _, err := SniffE(filepath.Join(t.TempDir(), "none.csv"), 0); err == nil {
+		t.Error("SniffE() error = nil, want an error")
+	}
+}
+
+func TestNewSniffedE(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "item.txt")
+	if err := os.WriteFile(path, []byte("id|name\n1|sword\n2|shi\"eld\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	separatedValue, sniffed, err := NewSniffedE(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sniffed.HasId {
+		t.Error("NewSniffedE() HasId = false, want true")
+	}
+	if separatedValue.GetExtension() != ".txt" {
+		t.Errorf("GetExtension() = %v, want .txt", separatedValue.GetExtension())
+	}
+
+	got, err := separatedValue.LoadE(path, true, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{{"id", "name"}, {"1", "sword"}, {"2", "shi\"eld"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadE() = %v, want %v", got, want)
+	}
+
+	if _, _, err := NewSniffedE(path, 0, WithExtension("txt")); err == nil {
+		t.Error("NewSniffedE() error = nil, want an error for the overridden extension")
+	}
+}
@@ -0,0 +1,484 @@
+package separated_value
+
+import (
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stepupdream/golang-support-tool/directory"
+	supportFile "github.com/stepupdream/golang-support-tool/file"
+)
+
+type Dialect string
+
+const (
+	MySQL      Dialect = "mysql"
+	SQLite     Dialect = "sqlite"
+	PostgreSQL Dialect = "postgresql"
+)
+
+// DefaultBatchSize The number of rows of one INSERT statement when the batch size is not specified.
+const DefaultBatchSize = 100
+
+// SQLExporter Converts separated value files into SQL statements of a dialect.
+// The table is the file name without the extension, e.g. "item" for item.csv.
+type SQLExporter struct {
+	separatedValue *SeparatedValue
+	dialect        Dialect
+	batchSize      int
+}
+
+// NewSQLExporter When batchSize is 0 or less, DefaultBatchSize is used.
+func (separatedValue *SeparatedValue) NewSQLExporter(dialect Dialect, batchSize int) (*SQLExporter, error) {
+	switch dialect {
+	case MySQL, SQLite, PostgreSQL:
+	default:
+		return nil, &Error{Err: errors.Errorf("Unknown SQL dialect : %s", dialect)}
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	return &SQLExporter{
+		separatedValue: separatedValue,
+		dialect:        dialect,
+		batchSize:      batchSize,
+	}, nil
+}
+
+func (exporter *SQLExporter) Statements(filePath string) []string {
+	statements, err := exporter.StatementsE(filePath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return statements
+}
+
+// StatementsE Returns the CREATE TABLE statement and the INSERT statements of the file, with the schema of the file (see SchemaFor).
+func (exporter *SQLExporter) StatementsE(filePath string) ([]string, error) {
+	schema, err := exporter.separatedValue.SchemaFor(filePath)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := exporter.loadRows(filePath)
+	if err != nil {
+		return nil, err
+	}
+	table := supportFile.GetNameWithoutExtension(filePath)
+
+	createTable, err := exporter.CreateTableE(table, rows[0], schema)
+	if err != nil {
+		return nil, err
+	}
+	inserts, err := exporter.InsertE(table, rows, schema, false)
+	if err != nil {
+		return nil, withFilePath(err, filePath)
+	}
+
+	return append([]string{createTable}, inserts...), nil
+}
+
+func (exporter *SQLExporter) VersionStatements(directoryPath string, fileName string) []string {
+	statements, err := exporter.VersionStatementsE(directoryPath, fileName)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return statements
+}
+
+// VersionStatementsE Returns the statements equivalent to LoadByDirectoryPath for the file of a version directory:
+// DELETE for the delete directory, UPDATE of the written columns for the update directory and INSERT for the insert directory,
+// in that order.
+func (exporter *SQLExporter) VersionStatementsE(directoryPath string, fileName string) ([]string, error) {
+	loadTypes := []string{"delete", "update", "insert"}
+	if !directory.Exist(directoryPath+"/"+loadTypes[0]+"/") &&
+		!directory.Exist(directoryPath+"/"+loadTypes[1]+"/") &&
+		!directory.Exist(directoryPath+"/"+loadTypes[2]+"/") {
+		return nil, &Error{FilePath: directoryPath, Err: errors.New("Neither insert/update/delete directories were found")}
+	}
+	table := supportFile.GetNameWithoutExtension(fileName)
+
+	var statements []string
+	for _, loadType := range loadTypes {
+		loadTypePath := directoryPath + "/" + loadType + "/"
+		if !directory.Exist(loadTypePath) {
+			continue
+		}
+
+		separatedValueFilePaths, err := exporter.separatedValue.GetFilePathRecursive(loadTypePath)
+		if err != nil {
+			return nil, &Error{FilePath: loadTypePath, Err: errors.Wrap(err, "GetFilePathRecursiveError")}
+		}
+
+		for _, filePath := range separatedValueFilePaths {
+			if fileName != filepath.Base(filePath) {
+				continue
+			}
+
+			schema, err := exporter.separatedValue.SchemaFor(filePath)
+			if err != nil {
+				return nil, err
+			}
+			rows, err := exporter.loadRows(filePath)
+			if err != nil {
+				return nil, err
+			}
+
+			var loadTypeStatements []string
+			switch loadType {
+			case "delete":
+				loadTypeStatements = exporter.delete(table, rows)
+			case "update":
+				loadTypeStatements, err = exporter.update(table, rows, schema)
+			case "insert":
+				loadTypeStatements, err = exporter.InsertE(table, rows, schema, false)
+			}
+			if err != nil {
+				return nil, withFilePath(err, filePath)
+			}
+			statements = append(statements, loadTypeStatements...)
+		}
+	}
+
+	return statements, nil
+}
+
+// WriteFileE Writes the statements to path, one per line.
+func (exporter *SQLExporter) WriteFileE(path string, statements []string) error {
+	content := strings.Join(statements, "\n")
+	if len(statements) != 0 {
+		content += "\n"
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return &Error{FilePath: path, Err: errors.Wrap(err, "WriteSQLFileError")}
+	}
+
+	return nil
+}
+
+// CreateTableE Returns the CREATE TABLE statement of the header. The column types are taken from the schema,
+// and the columns without a schema are text, except "id" which is an integer and the primary key.
+func (exporter *SQLExporter) CreateTableE(table string, header []string, schema *Schema) (string, error) {
+	var lines []string
+	isIdExist := false
+	for _, name := range header {
+		line := "  " + exporter.quoteIdentifier(name) + " " + exporter.columnType(name, schema)
+		if name == "id" {
+			isIdExist = true
+		}
+		if column, ok := schemaColumn(schema, name); (ok && !column.Nullable) || name == "id" {
+			line += " NOT NULL"
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		return "", &Error{Err: errors.Errorf("The table has no columns : %s", table)}
+	}
+	if isIdExist {
+		lines = append(lines, "  PRIMARY KEY ("+exporter.quoteIdentifier("id")+")")
+	}
+
+	return "CREATE TABLE " + exporter.quoteIdentifier(table) + " (\n" + strings.Join(lines, ",\n") + "\n);", nil
+}
+
+// InsertE Returns the INSERT statements of rows whose first row is the header, batchSize rows per statement.
+// When isUpsert is true, a row whose id already exists updates it instead.
+// A value that does not match its column in the schema is a SchemaError, whose Row is the index of the row plus one.
+func (exporter *SQLExporter) InsertE(table string, rows [][]string, schema *Schema, isUpsert bool) ([]string, error) {
+	if len(rows) <= 1 {
+		return nil, nil
+	}
+	header := rows[0]
+
+	var columns []string
+	for _, name := range header {
+		columns = append(columns, exporter.quoteIdentifier(name))
+	}
+	prefix := "INSERT INTO " + exporter.quoteIdentifier(table) + " (" + strings.Join(columns, ", ") + ") VALUES\n"
+
+	var statements []string
+	for start := 1; start < len(rows); start += exporter.batchSize {
+		end := start + exporter.batchSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+
+		var values []string
+		for index := start; index < end; index++ {
+			literals, err := exporter.literals(header, rows[index], schema)
+			if err != nil {
+				var schemaError *SchemaError
+				if errors.As(err, &schemaError) {
+					schemaError.Row = index + 1
+				}
+				return nil, err
+			}
+			values = append(values, "("+strings.Join(literals, ", ")+")")
+		}
+
+		statement := prefix + strings.Join(values, ",\n")
+		if isUpsert {
+			statement += exporter.upsertClause(header)
+		}
+		statements = append(statements, statement+";")
+	}
+
+	return statements, nil
+}
+
+func (exporter *SQLExporter) delete(table string, rows [][]string) []string {
+	idColumn := indexOf(rows[0], "id")
+	if len(rows) <= 1 || idColumn < 0 {
+		return nil
+	}
+
+	var ids []string
+	for _, row := range rows[1:] {
+		ids = append(ids, row[idColumn])
+	}
+
+	return []string{"DELETE FROM " + exporter.quoteIdentifier(table) + " WHERE " + exporter.quoteIdentifier("id") + " IN (" + strings.Join(ids, ", ") + ");"}
+}
+
+func (exporter *SQLExporter) update(table string, rows [][]string, schema *Schema) ([]string, error) {
+	header := rows[0]
+	idColumn := indexOf(header, "id")
+	if idColumn < 0 {
+		return nil, nil
+	}
+
+	var statements []string
+	for index, row := range rows[1:] {
+		literals, err := exporter.literals(header, row, schema)
+		if err != nil {
+			var schemaError *SchemaError
+			if errors.As(err, &schemaError) {
+				schemaError.Row = index + 2
+			}
+			return nil, err
+		}
+
+		var assignments []string
+		for columnNumber, name := range header {
+			if columnNumber != idColumn {
+				assignments = append(assignments, exporter.quoteIdentifier(name)+" = "+literals[columnNumber])
+			}
+		}
+		if len(assignments) == 0 {
+			continue
+		}
+		statements = append(statements, "UPDATE "+exporter.quoteIdentifier(table)+" SET "+strings.Join(assignments, ", ")+
+			" WHERE "+exporter.quoteIdentifier("id")+" = "+literals[idColumn]+";")
+	}
+
+	return statements, nil
+}
+
+// loadRows Loads the file with LoadMapE (so it is validated like the other loaders) and returns its rows ordered by id.
+func (exporter *SQLExporter) loadRows(filePath string) ([][]string, error) {
+	separatedValueMap, err := exporter.separatedValue.LoadMapE(filePath, nil, true)
+	if err != nil {
+		return nil, err
+	}
+	header, err := exporter.separatedValue.LoadHeaderE(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return exporter.separatedValue.ConvertRows(separatedValueMap, header), nil
+}
+
+func (exporter *SQLExporter) upsertClause(header []string) string {
+	var assignments []string
+	for _, name := range header {
+		if name == "id" {
+			continue
+		}
+		column := exporter.quoteIdentifier(name)
+		switch exporter.dialect {
+		case MySQL:
+			assignments = append(assignments, column+" = VALUES("+column+")")
+		case PostgreSQL:
+			assignments = append(assignments, column+" = EXCLUDED."+column)
+		default:
+			assignments = append(assignments, column+" = excluded."+column)
+		}
+	}
+
+	if exporter.dialect == MySQL {
+		if len(assignments) == 0 {
+			id := exporter.quoteIdentifier("id")
+			assignments = append(assignments, id+" = "+id)
+		}
+		return "\nON DUPLICATE KEY UPDATE " + strings.Join(assignments, ", ")
+	}
+	if len(assignments) == 0 {
+		return "\nON CONFLICT (" + exporter.quoteIdentifier("id") + ") DO NOTHING"
+	}
+
+	return "\nON CONFLICT (" + exporter.quoteIdentifier("id") + ") DO UPDATE SET " + strings.Join(assignments, ", ")
+}
+
+func (exporter *SQLExporter) literals(header []string, row []string, schema *Schema) ([]string, error) {
+	literals := make([]string, 0, len(header))
+	for columnNumber, name := range header {
+		value := ""
+		if columnNumber < len(row) {
+			value = row[columnNumber]
+		}
+
+		literal, err := exporter.literal(name, value, schema)
+		if err != nil {
+			return nil, err
+		}
+		literals = append(literals, literal)
+	}
+
+	return literals, nil
+}
+
+// literal Converts a cell into an SQL literal. A blank cell of a nullable column is NULL.
+func (exporter *SQLExporter) literal(name string, value string, schema *Schema) (string, error) {
+	column, ok := schemaColumn(schema, name)
+	if !ok {
+		if name == "id" {
+			if _, err := strconv.Atoi(value); err != nil {
+				return "", &SchemaError{Column: name, Value: value, Reason: "not an int"}
+			}
+			return value, nil
+		}
+		return exporter.quoteString(value), nil
+	}
+	if value == "" && column.Nullable {
+		return "NULL", nil
+	}
+	if reason := schema.check(name, value); reason != "" {
+		return "", &SchemaError{Column: name, Value: value, Reason: reason}
+	}
+
+	switch column.Type {
+	case IntColumn:
+		number, _ := strconv.Atoi(value)
+		return strconv.Itoa(number), nil
+	case FloatColumn:
+		number, _ := strconv.ParseFloat(value, 64)
+		if math.IsNaN(number) || math.IsInf(number, 0) {
+			return "", &SchemaError{Column: name, Value: value, Reason: "not a finite float"}
+		}
+		return strconv.FormatFloat(number, 'f', -1, 64), nil
+	case BoolColumn:
+		boolean, _ := strconv.ParseBool(value)
+		if exporter.dialect == PostgreSQL {
+			return strings.ToUpper(strconv.FormatBool(boolean)), nil
+		}
+		if boolean {
+			return "1", nil
+		}
+		return "0", nil
+	case DateTimeColumn:
+		layout := column.Layout
+		if layout == "" {
+			layout = DefaultDateTimeLayout
+		}
+		dateTime, _ := time.Parse(layout, value)
+		return exporter.quoteString(dateTime.Format(DefaultDateTimeLayout)), nil
+	}
+
+	return exporter.quoteString(value), nil
+}
+
+func (exporter *SQLExporter) columnType(name string, schema *Schema) string {
+	columnType := StringColumn
+	if column, ok := schemaColumn(schema, name); ok {
+		columnType = column.Type
+	} else if name == "id" {
+		columnType = IntColumn
+	}
+
+	switch exporter.dialect {
+	case MySQL:
+		switch columnType {
+		case IntColumn:
+			return "BIGINT"
+		case FloatColumn:
+			return "DOUBLE"
+		case BoolColumn:
+			return "TINYINT(1)"
+		case DateTimeColumn:
+			return "DATETIME"
+		}
+		// TEXT cannot be a key in MySQL, so a string id or a string with a maximum length is VARCHAR.
+		if column, ok := schemaColumn(schema, name); ok && column.MaxLength != nil {
+			return "VARCHAR(" + strconv.Itoa(*column.MaxLength) + ")"
+		}
+		if name == "id" {
+			return "VARCHAR(255)"
+		}
+		return "TEXT"
+	case PostgreSQL:
+		switch columnType {
+		case IntColumn:
+			return "BIGINT"
+		case FloatColumn:
+			return "DOUBLE PRECISION"
+		case BoolColumn:
+			return "BOOLEAN"
+		case DateTimeColumn:
+			return "TIMESTAMP"
+		}
+		return "TEXT"
+	}
+
+	switch columnType {
+	case IntColumn, BoolColumn:
+		return "INTEGER"
+	case FloatColumn:
+		return "REAL"
+	}
+	return "TEXT"
+}
+
+func (exporter *SQLExporter) quoteIdentifier(name string) string {
+	if exporter.dialect == MySQL {
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	}
+
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// quoteString MySQL treats a backslash in a string literal as an escape character, so it is escaped as well.
+func (exporter *SQLExporter) quoteString(value string) string {
+	if exporter.dialect == MySQL {
+		value = strings.ReplaceAll(value, `\`, `\\`)
+	}
+
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+func schemaColumn(schema *Schema, name string) (Column, bool) {
+	if schema == nil {
+		return Column{}, false
+	}
+
+	return schema.column(name)
+}
+
+// withFilePath Sets the file path of a SchemaError or an Error that was made without it.
+func withFilePath(err error, filePath string) error {
+	var schemaError *SchemaError
+	var pathError *Error
+	if errors.As(err, &schemaError) && schemaError.FilePath == "" {
+		schemaError.FilePath = filePath
+	} else if errors.As(err, &pathError) && pathError.FilePath == "" {
+		pathError.FilePath = filePath
+	}
+
+	return err
+}
@@ -0,0 +1,153 @@
+package separated_value
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestSQLExporterStatementsE(t *testing.T) {
+	var separatedValue SeparatedValue
+	separatedValue.Init("csv", ".csv")
+
+	tests := []struct {
+		name    string
+		dialect Dialect
+		want    []string
+	}{
+		{
+			name:    "mysql",
+			dialect: MySQL,
+			want: []string{
+				"CREATE TABLE `item` (\n" +
+					"  `id` BIGINT NOT NULL,\n" +
+					"  `name` VARCHAR(20) NOT NULL,\n" +
+					"  `price` BIGINT NOT NULL,\n" +
+					"  `is_sale` TINYINT(1) NOT NULL,\n" +
+					"  `memo` TEXT,\n" +
+					"  PRIMARY KEY (`id`)\n" +
+					");",
+				"INSERT INTO `item` (`id`, `name`, `price`, `is_sale`, `memo`) VALUES\n" +
+					"(1, 'sword', 100, 1, 'a\\\\b');",
+				"INSERT INTO `item` (`id`, `name`, `price`, `is_sale`, `memo`) VALUES\n" +
+					"(2, 'it''s', 200, 0, NULL);",
+			},
+		},
+		{
+			name:    "sqlite",
+			dialect: SQLite,
+			want: []string{
+				"CREATE TABLE \"item\" (\n" +
+					"  \"id\" INTEGER NOT NULL,\n" +
+					"  \"name\" TEXT NOT NULL,\n" +
+					"  \"price\" INTEGER NOT NULL,\n" +
+					"  \"is_sale\" INTEGER NOT NULL,\n" +
+					"  \"memo\" TEXT,\n" +
+					"  PRIMARY KEY (\"id\")\n" +
+					");",
+				"INSERT INTO \"item\" (\"id\", \"name\", \"price\", \"is_sale\", \"memo\") VALUES\n" +
+					"(1, 'sword', 100, 1, 'a\\b');",
+				"INSERT INTO \"item\" (\"id\", \"name\", \"price\", \"is_sale\", \"memo\") VALUES\n" +
+					"(2, 'it''s', 200, 0, NULL);",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter, err := separatedValue.NewSQLExporter(tt.dialect, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := exporter.StatementsE("./test/sql/item.csv")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("StatementsE() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSQLExporterVersionStatementsE(t *testing.T) {
+	var separatedValue SeparatedValue
+	separatedValue.Init("csv", ".csv")
+
+	exporter, err := separatedValue.NewSQLExporter(SQLite, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := exporter.VersionStatementsE("./test/sql/1_0_0_0", "item.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"DELETE FROM \"item\" WHERE \"id\" IN (2);",
+		"UPDATE \"item\" SET \"name\" = 'blade' WHERE \"id\" = 1;",
+		"INSERT INTO \"item\" (\"id\", \"name\") VALUES\n(3, 'bow');",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("VersionStatementsE() = %q, want %q", got, want)
+	}
+}
+
+func TestSQLExporterInsertE(t *testing.T) {
+	var separatedValue SeparatedValue
+	separatedValue.Init("csv", ".csv")
+	schema := &Schema{Columns: []Column{{Name: "id", Type: IntColumn}, {Name: "price", Type: IntColumn}}}
+	rows := [][]string{{"id", "price"}, {"1", "100"}, {"2", "200"}, {"3", "300"}}
+
+	tests := []struct {
+		name    string
+		dialect Dialect
+		want    []string
+	}{
+		{
+			name:    "mysql",
+			dialect: MySQL,
+			want: []string{
+				"INSERT INTO `item` (`id`, `price`) VALUES\n(1, 100),\n(2, 200)\nON DUPLICATE KEY UPDATE `price` = VALUES(`price`);",
+				"INSERT INTO `item` (`id`, `price`) VALUES\n(3, 300)\nON DUPLICATE KEY UPDATE `price` = VALUES(`price`);",
+			},
+		},
+		{
+			name:    "postgresql",
+			dialect: PostgreSQL,
+			want: []string{
+				"INSERT INTO \"item\" (\"id\", \"price\") VALUES\n(1, 100),\n(2, 200)\nON CONFLICT (\"id\") DO UPDATE SET \"price\" = EXCLUDED.\"price\";",
+				"INSERT INTO \"item\" (\"id\", \"price\") VALUES\n(3, 300)\nON CONFLICT (\"id\") DO UPDATE SET \"price\" = EXCLUDED.\"price\";",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter, err := separatedValue.NewSQLExporter(tt.dialect, 2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := exporter.InsertE("item", rows, schema, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("InsertE() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("schema error", func(t *testing.T) {
+		exporter, _ := separatedValue.NewSQLExporter(MySQL, 0)
+		_, err := exporter.InsertE("item", [][]string{{"id", "price"}, {"1", "free"}}, schema, false)
+		var schemaError *SchemaError
+		if !errors.As(err, &schemaError) || schemaError.Row != 2 || schemaError.Column != "price" {
+			t.Errorf("InsertE() error = %v", err)
+		}
+	})
+
+	t.Run("unknown dialect", func(t *testing.T) {
+		if _, err := separatedValue.NewSQLExporter("oracle", 0); err == nil {
+			t.Error("NewSQLExporter() error = nil")
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package separated_value
+
+import (
+	"strings"
+)
+
+// ValidationErrors All errors found by walking the whole file (or the whole directory) without stopping at the first one.
+type ValidationErrors []error
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "\n")
+}
+
+// Unwrap Lets errors.Is and errors.As find an error among them.
+func (e ValidationErrors) Unwrap() []error {
+	return e
+}
+
+// collector Decides whether to stop at an error or to continue collecting.
+// A nil collector stops at the first error.
+type collector struct {
+	errors ValidationErrors
+}
+
+// add Returns the error as it is when the caller should stop, otherwise records it and returns nil.
+func (c *collector) add(err error) error {
+	if c == nil {
+		return err
+	}
+
+	c.errors = append(c.errors, err)
+
+	return nil
+}
+
+func (c *collector) err() error {
+	if len(c.errors) == 0 {
+		return nil
+	}
+
+	return c.errors
+}
+
+// ValidateMap Same as LoadMapE, but reports every duplicate id, blank cell and missing id column in the file at once.
+// The returned error is ValidationErrors. The map contains the records that passed validation.
+func (separatedValue *SeparatedValue) ValidateMap(filePath string, filterNames []string, isColumnExclusion bool) (map[Key]string, error) {
+	collector := &collector{}
+	result, _ := separatedValue.loadMap(filePath, filterNames, isColumnExclusion, collector)
+
+	return result, collector.err()
+}
+
+// ValidateByDirectoryPath Same as LoadByDirectoryPathE, but walks every insert/update/delete file and reports all errors at once.
+// The returned error is ValidationErrors. Invalid records are skipped and the valid ones are applied to the map.
+func (separatedValue *SeparatedValue) ValidateByDirectoryPath(directoryPath string, fileName string, baseMap map[Key]string, filterNames []string) (map[Key]string, error) {
+	collector := &collector{}
+	result, _ := separatedValue.loadByDirectoryPath(directoryPath, fileName, baseMap, filterNames, nil, nil, collector)
+
+	return result, collector.err()
+}
+
+// ValidateRecordByDirectoryPath Same as ValidateByDirectoryPath, but the records are identified by the columns of the definition.
+func (separatedValue *SeparatedValue) ValidateRecordByDirectoryPath(directoryPath string, fileName string, definition KeyDefinition, baseMap map[RecordKey]string, filterNames []string) (map[RecordKey]string, error) {
+	collector := &collector{}
+	result, _ := separatedValue.loadRecordByDirectoryPath(directoryPath, fileName, definition, baseMap, filterNames, nil, nil, collector)
+
+	return result, collector.err()
+}
@@ -0,0 +1,80 @@
+package separated_value
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestValidateMap(t *testing.T) {
+	var separatedValue SeparatedValue
+	separatedValue.Init("csv", ".csv")
+
+	_, err := separatedValue.ValidateMap("test/validation/item.csv", []string{}, true)
+	want := ValidationErrors{
+		&BlankCellError{FilePath: "test/validation/item.csv", Row: 2, Column: "level", Id: 1},
+		&DuplicateIDError{FilePath: "test/validation/item.csv", Row: 3, Column: "id", Id: 1},
+		&BlankCellError{FilePath: "test/validation/item.csv", Row: 4, Column: "name", Id: 2},
+	}
+	if !reflect.DeepEqual(err, want) {
+		t.Errorf("ValidateMap() error = %v, want %v", err, want)
+	}
+}
+
+func TestValidationErrorsAs(t *testing.T) {
+	var separatedValue SeparatedValue
+	separatedValue.Init("csv", ".csv")
+
+	_, err := separatedValue.ValidateMap("test/validation/item.csv", []string{}, true)
+	var duplicateIDError *DuplicateIDError
+	if !errors.As(err, &duplicateIDError) {
+		t.Fatalf("errors.As() = false, want the DuplicateIDError in %v", err)
+	}
+	if duplicateIDError.Row != 3 {
+		t.Errorf("errors.As() Row = %d, want 3", duplicateIDError.Row)
+	}
+}
+
+func TestValidateByDirectoryPath(t *testing.T) {
+	var separatedValue SeparatedValue
+	separatedValue.Init("csv", ".csv")
+
+	baseMap := map[Key]string{
+		{Id: 1, Key: "id"}: "1", {Id: 1, Key: "name"}: "aaa",
+		{Id: 2, Key: "id"}: "2", {Id: 2, Key: "name"}: "bbb",
+	}
+	got, err := separatedValue.ValidateByDirectoryPath("test/validation/1_0_0_0", "item.csv", baseMap, []string{})
+	want := map[Key]string{
+		{Id: 1, Key: "id"}: "1", {Id: 1, Key: "name"}: "aaa",
+		{Id: 2, Key: "id"}: "2", {Id: 2, Key: "name"}: "bbb",
+		{Id: 3, Key: "id"}: "3", {Id: 3, Key: "name"}: "ccc",
+	}
+	wantErr := ValidationErrors{
+		&UnknownIDError{FilePath: "test/validation/1_0_0_0/update/item.csv", Row: 2, Column: "id", Id: 9, Operation: "update"},
+		&ExistingIDError{FilePath: "test/validation/1_0_0_0/insert/item.csv", Row: 2, Column: "id", Id: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ValidateByDirectoryPath() = %v, want %v", got, want)
+	}
+	if !reflect.DeepEqual(err, wantErr) {
+		t.Errorf("ValidateByDirectoryPath() error = %v, want %v", err, wantErr)
+	}
+}
+
+func TestValidateByDirectoryPathDuplicateID(t *testing.T) {
+	var separatedValue SeparatedValue
+	separatedValue.Init("csv", ".csv")
+
+	baseMap := map[Key]string{
+		{Id: 1, Key: "id"}: "1", {Id: 1, Key: "name"}: "aaa",
+		{Id: 2, Key: "id"}: "2", {Id: 2, Key: "name"}: "bbb",
+	}
+	_, err := separatedValue.ValidateByDirectoryPath("test/validation/1_0_1_0", "item.csv", baseMap, []string{})
+	wantErr := ValidationErrors{
+		&DuplicateIDError{FilePath: "test/validation/1_0_1_0/insert/item.csv", Row: 3, Column: "id", Id: 2},
+	}
+	if !reflect.DeepEqual(err, wantErr) {
+		t.Errorf("ValidateByDirectoryPath() error = %v, want %v", err, wantErr)
+	}
+}